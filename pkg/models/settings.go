@@ -3,31 +3,281 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	// Time zone database is embedded, so server time zone can be resolved on
+	// hosts without system zoneinfo
+	_ "time/tzdata"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 )
 
 type PluginSettings struct {
-	ServerAddress string                `json:"serverAddress"`
-	Secrets       *SecretPluginSettings `json:"-"`
+	ServerAddress    string                `json:"serverAddress"`
+	SortObjects      bool                  `json:"sortObjects"`
+	ObjectPaths      bool                  `json:"showObjectPaths"`         // show full object path in object list labels
+	Timeouts         map[string]float64    `json:"timeouts"`                // request timeout overrides in seconds by query type, fractions allowed
+	Timeout          int                   `json:"timeout"`                 // request timeout in seconds
+	DialTimeout      int                   `json:"connectTimeout"`          // connection establishment timeout in seconds
+	DefaultTimeRange int                   `json:"defaultTimeRange"`        // fallback time range in seconds for queries without range
+	StatusColors     map[string]string     `json:"statusColors"`            // object status color overrides by status name, "hidden" excludes objects
+	QueryCacheTTL    int                   `json:"objectQueryCacheTTL"`     // object query cache time in seconds, 0 disables caching
+	WebUIAddress     string                `json:"webUiAddress"`            // NetXMS web UI base address for drill-down links
+	APIVersion       string                `json:"apiVersion"`              // grafana API version requested from server
+	MaxRequests      int                   `json:"maxConcurrentRequests"`   // maximum number of concurrent requests to server
+	MaxQueries       int                   `json:"maxQueriesPerRequest"`    // maximum number of queries processed per data request
+	HTTP2            string                `json:"http2"`                   // HTTP/2 usage: "auto" (default), "disabled" or "forced"
+	RetryOn429       bool                  `json:"retryOnRateLimit"`        // retry rate limited request once after delay given by Retry-After header
+	GzipRequests     bool                  `json:"gzipRequests"`            // compress large request bodies when server accepts gzip encoding
+	GzipMinSize      int                   `json:"gzipRequestThreshold"`    // minimum request body size in bytes to compress
+	LenientJSON      bool                  `json:"lenientJson"`             // tolerate trailing commas and bare NaN/Infinity in responses, may hide malformed data
+	SlowResponse     int                   `json:"slowResponseThresholdMs"` // response time in milliseconds after which slow response notice is shown
+	FailureMode      string                `json:"failureMode"`             // failed query result: "error" (default) or "empty" frame with notice
+	NullValues       []string              `json:"nullValues"`              // DCI values reported by server for missing data, converted to nulls
+	Healthy          []int                 `json:"healthyStatuses"`         // HTTP statuses treated as successful server response
+	TimeZone         string                `json:"serverTimezone"`          // server time zone for history time bounds, UTC if empty
+	AllowAck         bool                  `json:"allowAlarmAcknowledge"`   // allow Grafana editors and admins to acknowledge alarms using datasource API key
+	Secrets          *SecretPluginSettings `json:"-"`
+
+	queryTimeout time.Duration // timeout override selected by ForQueryType
 }
 
 type SecretPluginSettings struct {
 	ApiKey string `json:"apiKey"`
 }
 
+// DefaultTimeout is used for requests to NetXMS server when timeout is not configured
+const DefaultTimeout = 10 * time.Second
+
+// DefaultConnectTimeout limits connection establishment when connect timeout is not configured
+const DefaultConnectTimeout = 5 * time.Second
+
+// DefaultFallbackTimeRange is used for DCI queries without time range when fallback range is not configured
+const DefaultFallbackTimeRange = time.Hour
+
+// DefaultAPIVersion is grafana API version requested when version is not configured
+const DefaultAPIVersion = "1"
+
+// DefaultSlowResponseThreshold is response time after which slow response notice is shown when threshold is not configured
+const DefaultSlowResponseThreshold = 5 * time.Second
+
+// DefaultMaxConcurrentRequests limits concurrent requests to server when limit is not configured
+const DefaultMaxConcurrentRequests = 10
+
+// DefaultMaxQueriesPerRequest limits number of queries processed per data request when limit is not configured
+const DefaultMaxQueriesPerRequest = 100
+
+// DefaultNullValues are DCI values treated as missing data when sentinel list is not configured
+var DefaultNullValues = []string{"N/A", "-"}
+
+// DefaultHealthyStatuses are HTTP statuses treated as successful when status set is not configured
+var DefaultHealthyStatuses = []int{http.StatusOK}
+
+// DefaultGzipRequestThreshold is minimum size of compressed request body when threshold is not configured
+const DefaultGzipRequestThreshold = 64 * 1024
+
+// HTTP/2 usage modes
+const (
+	HTTP2Auto     = "auto"     // HTTP/2 is negotiated over TLS, HTTP/1.1 is used otherwise
+	HTTP2Disabled = "disabled" // only HTTP/1.1 is used
+	HTTP2Forced   = "forced"   // only HTTP/2 is used, including unencrypted connections
+)
+
+// Failed query result modes
+const (
+	FailureModeError = "error" // failed query reports error
+	FailureModeEmpty = "empty" // failed query returns empty frame with error notice
+)
+
 func LoadPluginSettings(source backend.DataSourceInstanceSettings) (*PluginSettings, error) {
-	settings := PluginSettings{}
+	settings := PluginSettings{
+		SortObjects: true,
+		RetryOn429:  true,
+	}
 	err := json.Unmarshal(source.JSONData, &settings)
 	if err != nil {
 		return nil, fmt.Errorf("could not unmarshal PluginSettings json: %w", err)
 	}
 
+	settings.ServerAddress, err = normalizeServerAddress(settings.ServerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.WebUIAddress = strings.TrimSpace(settings.WebUIAddress)
+
+	switch settings.HTTP2 {
+	case "":
+		settings.HTTP2 = HTTP2Auto
+	case HTTP2Auto, HTTP2Disabled, HTTP2Forced:
+	default:
+		return nil, fmt.Errorf("invalid HTTP/2 mode %q, use %q, %q or %q", settings.HTTP2, HTTP2Auto, HTTP2Disabled, HTTP2Forced)
+	}
+
+	switch settings.FailureMode {
+	case "":
+		settings.FailureMode = FailureModeError
+	case FailureModeError, FailureModeEmpty:
+	default:
+		return nil, fmt.Errorf("invalid failure mode %q, use %q or %q", settings.FailureMode, FailureModeError, FailureModeEmpty)
+	}
+
+	settings.TimeZone = strings.TrimSpace(settings.TimeZone)
+	if _, err := settings.ServerLocation(); err != nil {
+		return nil, err
+	}
+
 	settings.Secrets = loadSecretPluginSettings(source.DecryptedSecureJSONData)
 
 	return &settings, nil
 }
 
+// normalizeServerAddress trims the configured address and checks that it is an
+// absolute http(s) URL. The scheme must be given explicitly, since NetXMS
+// webAPI is often served over plain HTTP. Empty address is returned as is so
+// callers can report it.
+func normalizeServerAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", nil
+	}
+
+	if !strings.Contains(address, "://") {
+		return "", fmt.Errorf("invalid server address %q: scheme is missing, use http:// or https://", address)
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid server address %q: %w", address, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid server address %q: unsupported scheme %q", address, parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("invalid server address %q: missing host", address)
+	}
+
+	return parsed.String(), nil
+}
+
+// RequestTimeout returns query type timeout override, configured request
+// timeout or default one
+func (s *PluginSettings) RequestTimeout() time.Duration {
+	if s.queryTimeout > 0 {
+		return s.queryTimeout
+	}
+	if s.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return time.Duration(s.Timeout) * time.Second
+}
+
+// ConnectTimeout returns configured connection establishment timeout or default one
+func (s *PluginSettings) ConnectTimeout() time.Duration {
+	if s.DialTimeout <= 0 {
+		return DefaultConnectTimeout
+	}
+	return time.Duration(s.DialTimeout) * time.Second
+}
+
+// ForQueryType returns copy of settings with request timeout overridden for given query type, if configured
+func (s *PluginSettings) ForQueryType(queryType string) *PluginSettings {
+	timeout, ok := s.Timeouts[queryType]
+	if !ok || timeout <= 0 {
+		return s
+	}
+	settings := *s
+	settings.queryTimeout = time.Duration(timeout * float64(time.Second))
+	return &settings
+}
+
+// FallbackTimeRange returns configured fallback time range or default one
+func (s *PluginSettings) FallbackTimeRange() time.Duration {
+	if s.DefaultTimeRange <= 0 {
+		return DefaultFallbackTimeRange
+	}
+	return time.Duration(s.DefaultTimeRange) * time.Second
+}
+
+// ObjectQueryCacheTTL returns how long object query results may be cached, zero if caching is disabled
+func (s *PluginSettings) ObjectQueryCacheTTL() time.Duration {
+	if s.QueryCacheTTL <= 0 {
+		return 0
+	}
+	return time.Duration(s.QueryCacheTTL) * time.Second
+}
+
+// GetAPIVersion returns configured grafana API version or default one
+func (s *PluginSettings) GetAPIVersion() string {
+	if version := strings.TrimSpace(s.APIVersion); version != "" {
+		return version
+	}
+	return DefaultAPIVersion
+}
+
+// SlowResponseWarning returns configured slow response threshold or default one
+func (s *PluginSettings) SlowResponseWarning() time.Duration {
+	if s.SlowResponse <= 0 {
+		return DefaultSlowResponseThreshold
+	}
+	return time.Duration(s.SlowResponse) * time.Millisecond
+}
+
+// MaxConcurrentRequests returns configured limit of concurrent requests to server or default one
+func (s *PluginSettings) MaxConcurrentRequests() int {
+	if s.MaxRequests <= 0 {
+		return DefaultMaxConcurrentRequests
+	}
+	return s.MaxRequests
+}
+
+// MaxQueriesPerRequest returns configured limit of queries processed per data request or default one
+func (s *PluginSettings) MaxQueriesPerRequest() int {
+	if s.MaxQueries <= 0 {
+		return DefaultMaxQueriesPerRequest
+	}
+	return s.MaxQueries
+}
+
+// DCINullValues returns configured DCI values treated as missing data or default ones
+func (s *PluginSettings) DCINullValues() []string {
+	if s.NullValues == nil {
+		return DefaultNullValues
+	}
+	return s.NullValues
+}
+
+// IsHealthyStatus checks if HTTP status of server response is configured as successful
+func (s *PluginSettings) IsHealthyStatus(status int) bool {
+	if len(s.Healthy) == 0 {
+		return slices.Contains(DefaultHealthyStatuses, status)
+	}
+	return slices.Contains(s.Healthy, status)
+}
+
+// ServerLocation returns configured server time zone, UTC if not configured
+func (s *PluginSettings) ServerLocation() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server time zone %q: %w", s.TimeZone, err)
+	}
+	return location, nil
+}
+
+// GzipRequestThreshold returns configured minimum size of compressed request body or default one
+func (s *PluginSettings) GzipRequestThreshold() int {
+	if s.GzipMinSize <= 0 {
+		return DefaultGzipRequestThreshold
+	}
+	return s.GzipMinSize
+}
+
 func loadSecretPluginSettings(source map[string]string) *SecretPluginSettings {
 	if source == nil {
 		return &SecretPluginSettings{}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func settingsWithAddress(t *testing.T, address string) backend.DataSourceInstanceSettings {
+	t.Helper()
+	jsonData, err := json.Marshal(map[string]string{"serverAddress": address})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend.DataSourceInstanceSettings{JSONData: jsonData}
+}
+
+func TestLoadPluginSettingsServerAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected string
+	}{
+		{"trailing spaces", "  http://netxms.local:8000/  ", "http://netxms.local:8000/"},
+		{"uppercase scheme", "HTTP://netxms.local:8000", "http://netxms.local:8000"},
+		{"with path", "https://netxms.local/api", "https://netxms.local/api"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := LoadPluginSettings(settingsWithAddress(t, tt.address))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if settings.ServerAddress != tt.expected {
+				t.Errorf("Expected server address %q, got: %q", tt.expected, settings.ServerAddress)
+			}
+		})
+	}
+}
+
+func TestLoadPluginSettingsInvalidServerAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+	}{
+		{"missing scheme", "netxms.local"},
+		{"missing scheme with port and path", "netxms.local:8000/api"},
+		{"unsupported scheme", "ftp://netxms.local"},
+		{"missing host", "http://"},
+		{"space in host", "http://bad host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadPluginSettings(settingsWithAddress(t, tt.address)); err == nil {
+				t.Errorf("Expected error for address %q", tt.address)
+			}
+		})
+	}
+}
+
+func TestLoadPluginSettingsSortObjects(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.SortObjects {
+		t.Error("Expected object sorting to be enabled by default")
+	}
+
+	settings, err = LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"sortObjects": false}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.SortObjects {
+		t.Error("Expected object sorting to be disabled")
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.RequestTimeout() != DefaultTimeout {
+		t.Errorf("Expected default timeout, got: %v", settings.RequestTimeout())
+	}
+
+	settings, err = LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"timeout": 60}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.RequestTimeout() != 60*time.Second {
+		t.Errorf("Expected 60s timeout, got: %v", settings.RequestTimeout())
+	}
+}
+
+func TestRequestTimeoutForQueryType(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"timeout": 10, "timeouts": {"dciValues": 60, "alarms": 0, "serverInfo": 0.5}}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout := settings.ForQueryType("dciValues").RequestTimeout(); timeout != 60*time.Second {
+		t.Errorf("Expected 60s timeout for DCI values, got: %v", timeout)
+	}
+	if timeout := settings.ForQueryType("serverInfo").RequestTimeout(); timeout != 500*time.Millisecond {
+		t.Errorf("Expected fractional 0.5s timeout for server info, got: %v", timeout)
+	}
+	if timeout := settings.ForQueryType("alarms").RequestTimeout(); timeout != 10*time.Second {
+		t.Errorf("Expected global timeout for zero override, got: %v", timeout)
+	}
+	if timeout := settings.ForQueryType("summaryTables").RequestTimeout(); timeout != 10*time.Second {
+		t.Errorf("Expected global timeout without override, got: %v", timeout)
+	}
+	if settings.RequestTimeout() != 10*time.Second {
+		t.Error("Expected original settings to stay unchanged")
+	}
+}
+
+func TestDCINullValues(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(settings.DCINullValues(), DefaultNullValues) {
+		t.Errorf("Expected default null values, got: %v", settings.DCINullValues())
+	}
+
+	settings, err = LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"nullValues": ["NaN"]}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(settings.DCINullValues(), []string{"NaN"}) {
+		t.Errorf("Expected configured null values, got: %v", settings.DCINullValues())
+	}
+}
+
+func TestServerLocation(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location, _ := settings.ServerLocation(); location != time.UTC {
+		t.Errorf("Expected UTC by default, got: %v", location)
+	}
+
+	settings, err = LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"serverTimezone": "Europe/Riga"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location, _ := settings.ServerLocation(); location.String() != "Europe/Riga" {
+		t.Errorf("Expected Europe/Riga, got: %v", location)
+	}
+
+	if _, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"serverTimezone": "Mars/Olympus"}`)}); err == nil {
+		t.Error("Expected error for unknown time zone")
+	}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	settings := &PluginSettings{}
+	if settings.ConnectTimeout() != DefaultConnectTimeout {
+		t.Errorf("Expected default connect timeout, got: %v", settings.ConnectTimeout())
+	}
+	settings.DialTimeout = 2
+	if settings.ConnectTimeout() != 2*time.Second {
+		t.Errorf("Expected 2s connect timeout, got: %v", settings.ConnectTimeout())
+	}
+}
+
+func TestIsHealthyStatus(t *testing.T) {
+	settings := &PluginSettings{}
+	if !settings.IsHealthyStatus(200) || settings.IsHealthyStatus(204) {
+		t.Error("Expected only 200 to be healthy by default")
+	}
+	settings.Healthy = []int{200, 204}
+	if !settings.IsHealthyStatus(204) {
+		t.Error("Expected configured 204 to be healthy")
+	}
+}
+
+func TestHTTP2Mode(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.HTTP2 != HTTP2Auto {
+		t.Errorf("Expected auto HTTP/2 mode by default, got: %q", settings.HTTP2)
+	}
+	if _, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"http2": "sometimes"}`)}); err == nil {
+		t.Error("Expected error for invalid HTTP/2 mode")
+	}
+}
+
+func TestFailureMode(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.FailureMode != FailureModeError {
+		t.Errorf("Expected error failure mode by default, got: %q", settings.FailureMode)
+	}
+	if _, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"failureMode": "ignore"}`)}); err == nil {
+		t.Error("Expected error for invalid failure mode")
+	}
+}
+
+func TestRetryOnRateLimitDefault(t *testing.T) {
+	settings, err := LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.RetryOn429 {
+		t.Error("Expected rate limited requests to be retried by default")
+	}
+	settings, err = LoadPluginSettings(backend.DataSourceInstanceSettings{JSONData: []byte(`{"retryOnRateLimit": false}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.RetryOn429 {
+		t.Error("Expected retry to be disabled")
+	}
+}
+
+func TestMaxQueriesPerRequest(t *testing.T) {
+	settings := &PluginSettings{}
+	if settings.MaxQueriesPerRequest() != DefaultMaxQueriesPerRequest {
+		t.Errorf("Expected default limit, got: %d", settings.MaxQueriesPerRequest())
+	}
+	settings.MaxQueries = 20
+	if settings.MaxQueriesPerRequest() != 20 {
+		t.Errorf("Expected configured limit, got: %d", settings.MaxQueriesPerRequest())
+	}
+}
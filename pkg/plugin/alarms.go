@@ -0,0 +1,974 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+// maxAlarmDciLookups limits number of distinct DCIs whose values are joined to alarms
+const maxAlarmDciLookups = 100
+
+type alarmResponse struct {
+	Id       int32  `json:"Id"`
+	Severity string `json:"Severity"`
+	State    string `json:"State"`
+	Source   string `json:"Source"`
+	// Source object id, not provided by older servers
+	SourceId   *int64    `json:"Source Id,omitempty"`
+	Message    string    `json:"Message"`
+	Count      int32     `json:"Count"`
+	AckBy      string    `json:"Ack/Resolve by"`
+	Created    alarmTime `json:"Created"`
+	LastChange alarmTime `json:"Last Change"`
+	// Repeat count history, provided only by servers supporting it
+	CountHistory []alarmCountPoint `json:"Count History,omitempty"`
+	// Id of DCI which triggered the alarm, not set for alarms from events
+	DciId *int64 `json:"DCI Id,omitempty"`
+}
+
+// alarmTime accepts alarm timestamps in formats used by different server
+// versions: RFC3339 string, "YYYY-MM-DD hh:mm:ss" string or UNIX epoch number
+// in seconds or milliseconds
+type alarmTime struct {
+	time.Time
+}
+
+// alarmTimeLayouts lists supported string formats of alarm and table timestamps
+var alarmTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// epochMillisecondsThreshold separates epoch values in seconds from values in milliseconds
+const epochMillisecondsThreshold = 1e11
+
+func (t *alarmTime) UnmarshalJSON(raw []byte) error {
+	if string(raw) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	var epoch float64
+	if err := json.Unmarshal(raw, &epoch); err == nil {
+		if epoch >= epochMillisecondsThreshold {
+			t.Time = time.UnixMilli(int64(epoch)).UTC()
+		} else {
+			t.Time = time.Unix(int64(epoch), 0).UTC()
+		}
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return fmt.Errorf("alarm time must be string or number: %w", err)
+	}
+	if str == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	if epoch, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return t.UnmarshalJSON([]byte(strconv.FormatInt(epoch, 10)))
+	}
+	if parsed, ok := parseTimestamp(str); ok {
+		t.Time = parsed
+		return nil
+	}
+	return fmt.Errorf("unsupported alarm time format: %q", str)
+}
+
+type alarmCountPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Count     int32     `json:"count"`
+}
+
+// QueryData handles multiple queries and returns multiple responses.
+// req contains the queries []DataQuery (where each query contains RefID as a unique identifier).
+// The QueryDataResponse contains a map of RefID to the response for each query, and each response
+// contains Frames ([]*Frame).
+func (d *NetXMSDatasource) handleAlarmQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		var qm queryModel
+		err := json.Unmarshal(q.JSON, &qm)
+		if err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			return d.query(ctx, req.PluginContext, qm)
+		})
+	}
+
+	return response, nil
+}
+
+func (d *NetXMSDatasource) query(ctx context.Context, pCtx backend.PluginContext, qm queryModel) backend.DataResponse {
+	var response backend.DataResponse
+	rootObjectId := string(qm.SourceObjectId)
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+	}
+	config = config.ForQueryType("alarms")
+
+	client := d.httpClient(ctx, config)
+
+	statusURL := joinURL(config.ServerAddress, "v1/grafana/infinity/alarms")
+
+	var bodyBytes []byte
+	if rootObjectId != "" {
+		rootObjectIdNum, parseErr := strconv.ParseInt(rootObjectId, 10, 64)
+		if parseErr != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("invalid rootObjectId: %v", parseErr.Error()))
+		}
+		body := map[string]any{
+			"rootObjectId": rootObjectIdNum,
+		}
+		bodyBytes, err = json.Marshal(body)
+		if err != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err.Error()))
+		}
+	} else {
+		bodyBytes = []byte(`{}`)
+	}
+
+	request, err := newAuthenticatedRequest(ctx, http.MethodPost, statusURL, bodyBytes, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err.Error()))
+	}
+
+	result, err := client.Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err.Error()))
+	}
+	defer result.Body.Close()
+
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err.Error()))
+	}
+
+	if isEmptyResponse(qm.AllowEmptyResponse, result.StatusCode, body, config) {
+		if qm.LogFormat {
+			return backend.DataResponse{Frames: data.Frames{buildAlarmLogFrame(nil)}}
+		}
+		return selectAlarmFields(buildAlarmFrame(nil, config.WebUIAddress, time.Now()), qm.Fields)
+	}
+
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
+	}
+
+	if qm.RawJSON {
+		response.Frames = append(response.Frames, rawJSONFrame("alarms", body))
+		return response
+	}
+
+	alarms, total, err := parseAlarmResponse(body)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err.Error()))
+	}
+
+	if qm.MessageFilter != "" {
+		alarms, err = filterAlarmsByMessage(alarms, qm.MessageFilter, qm.MessageFilterRegex)
+		if err != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+		}
+	}
+	if qm.AckByFilter != "" {
+		alarms = filterAlarmsByAckUser(alarms, qm.AckByFilter)
+	}
+
+	switch {
+	case qm.LogFormat:
+		response.Frames = append(response.Frames, buildAlarmLogFrame(alarms))
+	case qm.GroupBySource:
+		response.Frames = append(response.Frames, buildAlarmSourceFrame(alarms))
+	default:
+		response = selectAlarmFields(buildAlarmFrame(alarms, config.WebUIAddress, time.Now()), qm.Fields)
+		if response.Error == nil && qm.WithDciValues {
+			response.Frames[0].Fields = append(response.Frames[0].Fields,
+				data.NewField("DCI Value", nil, d.alarmDciValues(ctx, config, alarms)))
+		}
+		if response.Error == nil && len(qm.FieldOrder) > 0 {
+			orderFields(response.Frames[0], qm.FieldOrder)
+		}
+	}
+	if response.Error != nil {
+		return response
+	}
+	if total != nil {
+		setTotalCount(response.Frames[0], *total)
+	}
+	if qm.CountHistory {
+		response.Frames = append(response.Frames, buildAlarmCountHistoryFrames(alarms)...)
+	}
+	return response
+}
+
+// alarmDciValues looks up latest value of DCI which triggered each alarm.
+// Value is null for alarms without DCI, for DCIs over lookup limit and when
+// lookup fails, so missing value doesn't fail the whole alarm query.
+func (d *NetXMSDatasource) alarmDciValues(ctx context.Context, config *models.PluginSettings, alarms []alarmResponse) []*string {
+	type dciKey struct {
+		objectId int64
+		dciId    int64
+	}
+	index := make(map[dciKey]int)
+	keys := []dciKey{}
+	for _, alarm := range alarms {
+		if alarm.SourceId == nil || alarm.DciId == nil || *alarm.DciId == 0 {
+			continue
+		}
+		key := dciKey{objectId: *alarm.SourceId, dciId: *alarm.DciId}
+		if _, ok := index[key]; !ok && len(keys) < maxAlarmDciLookups {
+			index[key] = len(keys)
+			keys = append(keys, key)
+		}
+	}
+
+	results := make([]*string, len(keys))
+	semaphore := make(chan struct{}, maxConcurrentDciRequests)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i] = d.fetchLatestDciValue(ctx, config, key.objectId, key.dciId)
+		}()
+	}
+	wg.Wait()
+
+	values := make([]*string, len(alarms))
+	for i, alarm := range alarms {
+		if alarm.SourceId == nil || alarm.DciId == nil {
+			continue
+		}
+		if j, ok := index[dciKey{objectId: *alarm.SourceId, dciId: *alarm.DciId}]; ok {
+			values[i] = results[j]
+		}
+	}
+	return values
+}
+
+// fetchLatestDciValue requests latest value of DCI, nil if it is not available
+func (d *NetXMSDatasource) fetchLatestDciValue(ctx context.Context, config *models.PluginSettings, objectId, dciId int64) *string {
+	historyURL := joinURL(config.ServerAddress, fmt.Sprintf("v1/objects/%d/data-collection/%d/history?%s", objectId, dciId, latestValueParameter))
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, historyURL, nil, config)
+	if err != nil {
+		return nil
+	}
+	result, err := d.httpClient(ctx, config).Do(request)
+	if err != nil {
+		return nil
+	}
+	defer result.Body.Close()
+	body, err := readResponseBody(result.Body, config)
+	if err != nil || !config.IsHealthyStatus(result.StatusCode) {
+		return nil
+	}
+	var dciData dciValueResponse
+	if err := json.Unmarshal(body, &dciData); err != nil {
+		return nil
+	}
+	latest := latestDciValue(dciData.Values)
+	if len(latest) == 0 {
+		return nil
+	}
+	return &latest[0].Value
+}
+
+// alarmListEnvelope is alarm list wrapped in object, as returned by some server endpoints
+type alarmListEnvelope struct {
+	Alarms json.RawMessage `json:"alarms"`
+	Total  *int64          `json:"total"`
+}
+
+// parseAlarmResponse parses alarm list given either as bare array or wrapped
+// in object with total count. Total is nil if server didn't report it. Object
+// without alarm list is reported as error, so that unexpected response isn't
+// shown as empty alarm table.
+func parseAlarmResponse(body []byte) ([]alarmResponse, *int64, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		var alarms []alarmResponse
+		if err := json.Unmarshal(body, &alarms); err != nil {
+			return nil, nil, err
+		}
+		return alarms, nil, nil
+	}
+
+	var envelope alarmListEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, nil, err
+	}
+	if envelope.Alarms == nil {
+		return nil, nil, errors.New(`response object has no "alarms" list`)
+	}
+	var alarms []alarmResponse
+	if err := json.Unmarshal(envelope.Alarms, &alarms); err != nil {
+		return nil, nil, err
+	}
+	return alarms, envelope.Total, nil
+}
+
+// setTotalCount reports total number of rows available on server in frame metadata
+func setTotalCount(frame *data.Frame, total int64) {
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	frame.Meta.Stats = append(frame.Meta.Stats, data.QueryStat{
+		FieldConfig: data.FieldConfig{DisplayName: "Total rows"},
+		Value:       float64(total),
+	})
+}
+
+// buildAlarmCountHistoryFrames creates repeat count series for each alarm with
+// count history. Alarms without history are skipped.
+func buildAlarmCountHistoryFrames(alarms []alarmResponse) data.Frames {
+	var frames data.Frames
+	for _, alarm := range alarms {
+		if len(alarm.CountHistory) == 0 {
+			continue
+		}
+		times := make([]time.Time, len(alarm.CountHistory))
+		counts := make([]int32, len(alarm.CountHistory))
+		for i, point := range alarm.CountHistory {
+			times[i] = point.Timestamp
+			counts[i] = point.Count
+		}
+		frames = append(frames, data.NewFrame(fmt.Sprintf("count-history-%d", alarm.Id),
+			data.NewField("time", nil, times),
+			data.NewField("count", data.Labels{"alarmId": strconv.Itoa(int(alarm.Id))}, counts),
+		))
+	}
+	return frames
+}
+
+// filterAlarmsByMessage keeps alarms with message containing given substring (case insensitive) or matching regular expression
+func filterAlarmsByMessage(alarms []alarmResponse, filter string, isRegex bool) ([]alarmResponse, error) {
+	var match func(string) bool
+	if isRegex {
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid messageFilter regular expression: %w", err)
+		}
+		match = re.MatchString
+	} else {
+		filter = strings.ToLower(filter)
+		match = func(message string) bool {
+			return strings.Contains(strings.ToLower(message), filter)
+		}
+	}
+
+	filtered := make([]alarmResponse, 0, len(alarms))
+	for _, alarm := range alarms {
+		if match(alarm.Message) {
+			filtered = append(filtered, alarm)
+		}
+	}
+	return filtered, nil
+}
+
+// filterAlarmsByAckUser keeps alarms acknowledged or resolved by given user (case insensitive)
+func filterAlarmsByAckUser(alarms []alarmResponse, user string) []alarmResponse {
+	user = strings.TrimSpace(user)
+	filtered := make([]alarmResponse, 0, len(alarms))
+	for _, alarm := range alarms {
+		if strings.EqualFold(strings.TrimSpace(alarm.AckBy), user) {
+			filtered = append(filtered, alarm)
+		}
+	}
+	return filtered
+}
+
+// buildAlarmFrame converts alarm list into data frame. Age of alarms is
+// computed in seconds relative to now, null for alarms without creation time.
+func buildAlarmFrame(alarms []alarmResponse, webUIAddress string, now time.Time) *data.Frame {
+	frame := data.NewFrame("alarms")
+
+	ids := make([]int32, len(alarms))
+	severities := make([]string, len(alarms))
+	severityLevels := make([]*int32, len(alarms))
+	states := make([]string, len(alarms))
+	sources := make([]string, len(alarms))
+	sourceIds := make([]*int64, len(alarms))
+	messages := make([]string, len(alarms))
+	counts := make([]int32, len(alarms))
+	ackBy := make([]string, len(alarms))
+	created := make([]time.Time, len(alarms))
+	lastChange := make([]time.Time, len(alarms))
+	ages := make([]*int64, len(alarms))
+
+	for i, alarm := range alarms {
+		ids[i] = alarm.Id
+		severities[i] = alarm.Severity
+		severityLevels[i] = objectStatusCode(alarm.Severity)
+		states[i] = alarm.State
+		sources[i] = alarm.Source
+		sourceIds[i] = alarm.SourceId
+		messages[i] = alarm.Message
+		counts[i] = alarm.Count
+		ackBy[i] = alarm.AckBy
+		created[i] = alarm.Created.Time
+		lastChange[i] = alarm.LastChange.Time
+		if !alarm.Created.IsZero() {
+			// Clock difference between server and Grafana may place creation in future
+			age := int64(max(now.Sub(alarm.Created.Time), 0) / time.Second)
+			ages[i] = &age
+		}
+	}
+
+	severityField := data.NewField("Severity", nil, severities)
+	severityField.Config = &data.FieldConfig{Mappings: alarmSeverityMappings()}
+	stateField := data.NewField("State", nil, states)
+	stateField.Config = &data.FieldConfig{
+		Mappings: data.ValueMappings{
+			data.ValueMapper{
+				"Outstanding":  {Text: "Outstanding", Color: "yellow"},
+				"Acknowledged": {Text: "Acknowledged", Color: "greenyellow"},
+				"Resolved":     {Text: "Resolved", Color: "green"},
+			},
+		},
+	}
+
+	ageField := data.NewField("Age", nil, ages)
+	ageField.Config = &data.FieldConfig{Unit: "s"}
+
+	sourceField := data.NewField("Source", nil, sources)
+	if links := objectLinks(webUIAddress, `${__data.fields["Source Id"]}`); links != nil {
+		sourceField.Config = &data.FieldConfig{Links: links}
+	}
+
+	frame.Fields = append(frame.Fields,
+		data.NewField("Id", nil, ids),
+		severityField,
+		data.NewField("Severity Level", nil, severityLevels),
+		stateField,
+		sourceField,
+		data.NewField("Source Id", nil, sourceIds),
+		data.NewField("Message", nil, messages),
+		data.NewField("Count", nil, counts),
+		data.NewField("Ack/Resolve by", nil, ackBy),
+		data.NewField("Created", nil, created),
+		data.NewField("Last Change", nil, lastChange),
+		ageField,
+	)
+
+	return frame
+}
+
+// alarmSeverityMappings colors alarm severity names
+func alarmSeverityMappings() data.ValueMappings {
+	return data.ValueMappings{
+		data.ValueMapper{
+			"Normal":    {Text: "Normal", Color: "rgb(0, 137, 0)"},
+			"Warning":   {Text: "Warning", Color: "rgb(0, 142, 145)"},
+			"Minor":     {Text: "Minor", Color: "rgb(201, 198, 0)"},
+			"Major":     {Text: "Major", Color: "rgb(223, 102, 0)"},
+			"Critical":  {Text: "Critical", Color: "rgb(160, 0, 0)"},
+			"Unknown":   {Text: "Unknown", Color: "rgb(33, 33, 248)"},
+			"Unmanaged": {Text: "Unmanaged", Color: "rgb(113, 113, 113)"},
+			"Disabled":  {Text: "Disabled", Color: "rgb(100, 41, 0)"},
+			"Testing":   {Text: "Testing", Color: "rgb(138, 0, 143)"},
+		},
+	}
+}
+
+// buildAlarmSourceFrame groups alarms by source object into rows with highest
+// severity, number of alarms and total repeat count. Sources keep order of
+// their first alarm.
+func buildAlarmSourceFrame(alarms []alarmResponse) *data.Frame {
+	type sourceKey struct {
+		name string
+		id   int64
+	}
+	index := make(map[sourceKey]int)
+	sources := []string{}
+	sourceIds := []*int64{}
+	severities := []string{}
+	alarmCounts := []int64{}
+	repeatCounts := []int64{}
+	for _, alarm := range alarms {
+		key := sourceKey{name: alarm.Source}
+		if alarm.SourceId != nil {
+			key = sourceKey{id: *alarm.SourceId}
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(sources)
+			index[key] = i
+			sources = append(sources, alarm.Source)
+			sourceIds = append(sourceIds, alarm.SourceId)
+			severities = append(severities, alarm.Severity)
+			alarmCounts = append(alarmCounts, 0)
+			repeatCounts = append(repeatCounts, 0)
+		}
+		if severityRank(alarm.Severity) > severityRank(severities[i]) {
+			severities[i] = alarm.Severity
+		}
+		alarmCounts[i]++
+		repeatCounts[i] += int64(alarm.Count)
+	}
+
+	severityField := data.NewField("Severity", nil, severities)
+	severityField.Config = &data.FieldConfig{Mappings: alarmSeverityMappings()}
+	return data.NewFrame("alarms-by-source",
+		data.NewField("Source", nil, sources),
+		data.NewField("Source Id", nil, sourceIds),
+		severityField,
+		data.NewField("Alarms", nil, alarmCounts),
+		data.NewField("Count", nil, repeatCounts),
+	)
+}
+
+// severityRank orders alarm severities, unknown names rank lowest
+func severityRank(severity string) int32 {
+	if code := objectStatusCode(severity); code != nil {
+		return *code
+	}
+	return -1
+}
+
+// handleAlarmCountQuery returns number of alarms matching filters as single value,
+// counted by server to avoid transferring full alarm list
+func (d *NetXMSDatasource) handleAlarmCountQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		var qm queryModel
+		if err := json.Unmarshal(q.JSON, &qm); err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			return d.alarmCountQuery(ctx, req.PluginContext, qm)
+		})
+	}
+
+	return response, nil
+}
+
+func (d *NetXMSDatasource) alarmCountQuery(ctx context.Context, pCtx backend.PluginContext, qm queryModel) backend.DataResponse {
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+	}
+	config = config.ForQueryType("alarmCount")
+
+	reqBody := make(map[string]any)
+	if qm.SourceObjectId != "" {
+		rootObjectId, parseErr := strconv.ParseInt(string(qm.SourceObjectId), 10, 64)
+		if parseErr != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("invalid rootObjectId: %v", parseErr.Error()))
+		}
+		reqBody["rootObjectId"] = rootObjectId
+	}
+	if len(qm.SeverityFilter) > 0 {
+		reqBody["severity"] = qm.SeverityFilter
+	}
+	if len(qm.StateFilter) > 0 {
+		reqBody["state"] = qm.StateFilter
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err.Error()))
+	}
+
+	countURL := joinURL(config.ServerAddress, "v1/grafana/infinity/alarm-count")
+	request, err := newAuthenticatedRequest(ctx, http.MethodPost, countURL, bodyBytes, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err.Error()))
+	}
+
+	result, err := d.httpClient(ctx, config).Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err.Error()))
+	}
+	defer result.Body.Close()
+
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err.Error()))
+	}
+
+	if isEmptyResponse(qm.AllowEmptyResponse, result.StatusCode, body, config) {
+		return backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("alarm-count", data.NewField("count", nil, []int64{}))},
+		}
+	}
+
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
+	}
+
+	var countResponse struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.Unmarshal(body, &countResponse); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err.Error()))
+	}
+
+	return backend.DataResponse{
+		Frames: data.Frames{data.NewFrame("alarm-count", data.NewField("count", nil, []int64{countResponse.Count}))},
+	}
+}
+
+// alarmEvent is single entry of alarm history
+type alarmEvent struct {
+	Time     alarmTime `json:"Time"`
+	AlarmId  int32     `json:"Alarm Id"`
+	Severity string    `json:"Severity"`
+	State    string    `json:"State"`
+	Source   string    `json:"Source"`
+	SourceId *int64    `json:"Source Id,omitempty"`
+	Message  string    `json:"Message"`
+}
+
+// handleAlarmHistoryQuery returns alarm state and severity transitions within
+// query time range as time-stamped events
+func (d *NetXMSDatasource) handleAlarmHistoryQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		var qm queryModel
+		if err := json.Unmarshal(q.JSON, &qm); err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			return d.alarmHistoryQuery(ctx, req.PluginContext, q.TimeRange, qm)
+		})
+	}
+
+	return response, nil
+}
+
+func (d *NetXMSDatasource) alarmHistoryQuery(ctx context.Context, pCtx backend.PluginContext, timeRange backend.TimeRange, qm queryModel) backend.DataResponse {
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+	}
+	config = config.ForQueryType("alarmHistory")
+
+	from, to := resolveTimeRange(timeRange, config)
+	timeFrom, timeTo, err := formatTimeBounds(from, to, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+	historyURL := joinURL(config.ServerAddress, fmt.Sprintf("v1/grafana/infinity/alarm-history?timeFrom=%s&timeTo=%s", timeFrom, timeTo))
+	if qm.SourceObjectId != "" {
+		rootObjectId, parseErr := strconv.ParseInt(string(qm.SourceObjectId), 10, 64)
+		if parseErr != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("invalid rootObjectId: %v", parseErr.Error()))
+		}
+		historyURL += "&rootObjectId=" + strconv.FormatInt(rootObjectId, 10)
+	}
+
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, historyURL, nil, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err.Error()))
+	}
+
+	result, err := d.httpClient(ctx, config).Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err.Error()))
+	}
+	defer result.Body.Close()
+
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err.Error()))
+	}
+
+	if isEmptyResponse(qm.AllowEmptyResponse, result.StatusCode, body, config) {
+		return backend.DataResponse{Frames: data.Frames{buildAlarmTransitionFrame(nil)}}
+	}
+
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
+	}
+
+	var events []alarmEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err.Error()))
+	}
+
+	return backend.DataResponse{Frames: data.Frames{buildAlarmTransitionFrame(events)}}
+}
+
+// buildAlarmTransitionFrame converts alarm history into events ordered by time.
+// History entries which don't change state or severity of the alarm (e.g.
+// repeat count updates) are skipped.
+func buildAlarmTransitionFrame(events []alarmEvent) *data.Frame {
+	events = slices.Clone(events)
+	slices.SortStableFunc(events, func(a, b alarmEvent) int {
+		return a.Time.Compare(b.Time.Time)
+	})
+
+	type alarmStatus struct {
+		severity string
+		state    string
+	}
+	last := make(map[int32]alarmStatus)
+	times := []time.Time{}
+	ids := []int32{}
+	severities := []string{}
+	severityLevels := []*int32{}
+	previousSeverities := []*string{}
+	states := []string{}
+	sources := []string{}
+	sourceIds := []*int64{}
+	messages := []string{}
+	for _, event := range events {
+		status := alarmStatus{severity: event.Severity, state: event.State}
+		previous, seen := last[event.AlarmId]
+		if seen && previous == status {
+			continue
+		}
+		last[event.AlarmId] = status
+
+		var previousSeverity *string
+		if seen {
+			previousSeverity = &previous.severity
+		}
+		times = append(times, event.Time.Time)
+		ids = append(ids, event.AlarmId)
+		severities = append(severities, event.Severity)
+		severityLevels = append(severityLevels, objectStatusCode(event.Severity))
+		previousSeverities = append(previousSeverities, previousSeverity)
+		states = append(states, event.State)
+		sources = append(sources, event.Source)
+		sourceIds = append(sourceIds, event.SourceId)
+		messages = append(messages, event.Message)
+	}
+
+	severityField := data.NewField("Severity", nil, severities)
+	severityField.Config = &data.FieldConfig{Mappings: alarmSeverityMappings()}
+	return data.NewFrame("alarm-transitions",
+		data.NewField("Time", nil, times),
+		data.NewField("Alarm Id", nil, ids),
+		severityField,
+		data.NewField("Severity Level", nil, severityLevels),
+		data.NewField("Previous Severity", nil, previousSeverities),
+		data.NewField("State", nil, states),
+		data.NewField("Source", nil, sources),
+		data.NewField("Source Id", nil, sourceIds),
+		data.NewField("Message", nil, messages),
+	)
+}
+
+// alarmLogLevels maps alarm severities to log levels recognized by Grafana Logs panel
+var alarmLogLevels = map[string]string{
+	"Normal":   "info",
+	"Warning":  "warning",
+	"Minor":    "warning",
+	"Major":    "error",
+	"Critical": "critical",
+}
+
+// buildAlarmLogFrame creates frame in data plane log format, so alarms can be shown in Logs panel
+func buildAlarmLogFrame(alarms []alarmResponse) *data.Frame {
+	timestamps := make([]time.Time, len(alarms))
+	bodies := make([]string, len(alarms))
+	severities := make([]string, len(alarms))
+	ids := make([]string, len(alarms))
+	labels := make([]json.RawMessage, len(alarms))
+
+	for i, alarm := range alarms {
+		timestamps[i] = alarm.LastChange.Time
+		bodies[i] = alarm.Message
+		severity, ok := alarmLogLevels[alarm.Severity]
+		if !ok {
+			severity = "unknown"
+		}
+		severities[i] = severity
+		ids[i] = strconv.FormatInt(int64(alarm.Id), 10)
+		labels[i], _ = json.Marshal(map[string]string{
+			"severity": alarm.Severity,
+			"state":    alarm.State,
+			"source":   alarm.Source,
+		})
+	}
+
+	frame := data.NewFrame("alarms",
+		data.NewField("timestamp", nil, timestamps),
+		data.NewField("body", nil, bodies),
+		data.NewField("severity", nil, severities),
+		data.NewField("id", nil, ids),
+		data.NewField("labels", nil, labels),
+	)
+	frame.Meta = &data.FrameMeta{
+		Type:                   data.FrameTypeLogLines,
+		TypeVersion:            data.FrameTypeVersion{0, 0},
+		PreferredVisualization: data.VisTypeLogs,
+	}
+	return frame
+}
+
+// selectAlarmFields keeps only requested alarm frame fields, all fields are kept if none are requested
+func selectAlarmFields(frame *data.Frame, fields []string) backend.DataResponse {
+	if len(fields) == 0 {
+		return backend.DataResponse{Frames: data.Frames{frame}}
+	}
+
+	requested := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		if _, idx := frame.FieldByName(name); idx < 0 {
+			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("unknown alarm field: %s", name))
+		}
+		requested[name] = true
+	}
+
+	selected := make([]*data.Field, 0, len(fields))
+	for _, field := range frame.Fields {
+		if requested[field.Name] {
+			selected = append(selected, field)
+		}
+	}
+	frame.Fields = selected
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// orderFields moves listed fields to the front of frame in listed order.
+// Other fields follow in their original order. Listed names missing in frame,
+// e.g. excluded by field selection, are ignored.
+func orderFields(frame *data.Frame, order []string) {
+	ordered := make([]*data.Field, 0, len(frame.Fields))
+	for _, name := range order {
+		if field, idx := frame.FieldByName(name); idx >= 0 && !slices.Contains(ordered, field) {
+			ordered = append(ordered, field)
+		}
+	}
+	for _, field := range frame.Fields {
+		if !slices.Contains(ordered, field) {
+			ordered = append(ordered, field)
+		}
+	}
+	frame.Fields = ordered
+}
+
+// maxAcknowledgeBatch limits number of alarms acknowledged by single request
+const maxAcknowledgeBatch = 100
+
+// acknowledgeRequest lists alarms to acknowledge, single alarm may be given by alarmId
+type acknowledgeRequest struct {
+	AlarmId  *int64  `json:"alarmId"`
+	AlarmIds []int64 `json:"alarmIds"`
+}
+
+// acknowledgeResult is outcome of acknowledging single alarm
+type acknowledgeResult struct {
+	Id      int64  `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// handleAcknowledgeAlarms acknowledges one or more alarms, returning result
+// for each alarm. Failure of one alarm doesn't stop acknowledgement of others.
+// Acknowledgement changes server state using datasource API key, so it must be
+// enabled in settings and is allowed only to editors and admins.
+func (ds *NetXMSDatasource) handleAcknowledgeAlarms(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSONError(rw, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, fmt.Sprintf("failed to load plugin settings: %v", err))
+		return
+	}
+	if !config.AllowAck {
+		writeJSONError(rw, http.StatusForbidden, "alarm acknowledgement is disabled in datasource settings")
+		return
+	}
+	if !canChangeAlarms(pCtx.User) {
+		writeJSONError(rw, http.StatusForbidden, "alarm acknowledgement requires Editor or Admin role")
+		return
+	}
+
+	var ack acknowledgeRequest
+	if err := json.NewDecoder(req.Body).Decode(&ack); err != nil {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	ids := ack.AlarmIds
+	if ack.AlarmId != nil {
+		ids = append(ids, *ack.AlarmId)
+	}
+	if len(ids) == 0 {
+		writeJSONError(rw, http.StatusBadRequest, "no alarm ids given")
+		return
+	}
+	if len(ids) > maxAcknowledgeBatch {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("too many alarms, at most %d can be acknowledged at once", maxAcknowledgeBatch))
+		return
+	}
+
+	client := ds.httpClient(req.Context(), config)
+	results := make([]acknowledgeResult, len(ids))
+	for i, id := range ids {
+		results[i] = acknowledgeResult{Id: id, Success: true}
+		if err := acknowledgeAlarm(req.Context(), client, config, id); err != nil {
+			results[i].Success = false
+			results[i].Error = err.Error()
+		}
+	}
+
+	response, err := json.Marshal(map[string][]acknowledgeResult{"results": results})
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to marshal response")
+		return
+	}
+	writeJSONResponse(rw, response)
+}
+
+// canChangeAlarms checks that Grafana user role allows changing alarm state
+func canChangeAlarms(user *backend.User) bool {
+	return user != nil && (user.Role == "Editor" || user.Role == "Admin")
+}
+
+// acknowledgeAlarm sends acknowledgement of single alarm to server
+func acknowledgeAlarm(ctx context.Context, client *http.Client, config *models.PluginSettings, id int64) error {
+	ackURL := joinURL(config.ServerAddress, fmt.Sprintf("v1/alarms/%d/acknowledge", id))
+	request, err := newAuthenticatedRequest(ctx, http.MethodPost, ackURL, nil, config)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	result, err := client.Do(request)
+	if err != nil {
+		return errors.New("failed to connect to server")
+	}
+	defer result.Body.Close()
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		return errors.New("failed to read response")
+	}
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res.Error
+	}
+	return nil
+}
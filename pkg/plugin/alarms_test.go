@@ -0,0 +1,614 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func TestAlarmMessageFilter(t *testing.T) {
+	server := mockAlarmListServer("Interface eth0 down", "CPU usage high", "Interface eth1 down")
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{"substring", `{"messageFilter": "interface"}`, 2},
+		{"regex", `{"messageFilter": "^CPU.*high$", "messageFilterRegex": true}`, 1},
+		{"no filter", `{}`, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := runTestQuery(t, newTestSettings(server.URL), "alarms", tt.query)
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got: %v", response.Error)
+			}
+			if rows := response.Frames[0].Rows(); rows != tt.expected {
+				t.Errorf("Expected %d alarms, got: %d", tt.expected, rows)
+			}
+		})
+	}
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"messageFilter": "(", "messageFilterRegex": true}`)
+	if response.Error == nil || !strings.Contains(response.Error.Error(), "invalid messageFilter") {
+		t.Errorf("Expected invalid regex error, got: %v", response.Error)
+	}
+}
+
+func TestAlarmCountHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Id":1,"Severity":"Major","Count":3,"Count History":[` +
+			`{"timestamp":"2026-01-01T00:00:00Z","count":1},{"timestamp":"2026-01-01T00:05:00Z","count":3}]},` +
+			`{"Id":2,"Severity":"Minor","Count":1}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"countHistory": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 2 {
+		t.Fatalf("Expected alarm frame and one history frame, got: %d", len(response.Frames))
+	}
+	history := response.Frames[1]
+	if history.Name != "count-history-1" || history.Rows() != 2 {
+		t.Errorf("Unexpected history frame %s with %d rows", history.Name, history.Rows())
+	}
+	if count, _ := history.Fields[1].At(1).(int32); count != 3 {
+		t.Errorf("Expected last count 3, got: %d", count)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`)
+	if len(response.Frames) != 1 {
+		t.Errorf("Expected only alarm frame without option, got: %d", len(response.Frames))
+	}
+}
+
+func TestAlarmSourceNameAndId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Id":1,"Source":"node1","Source Id":100,"Message":"Link down"},` +
+			`{"Id":2,"Source":"node2","Message":"CPU high"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	sourceField, _ := frame.FieldByName("Source")
+	sourceIdField, _ := frame.FieldByName("Source Id")
+	if sourceField == nil || sourceIdField == nil {
+		t.Fatal("Expected both Source and Source Id fields")
+	}
+	if name, _ := sourceField.At(0).(string); name != "node1" {
+		t.Errorf("Expected source name node1, got: %q", name)
+	}
+	if id, _ := sourceIdField.At(0).(*int64); id == nil || *id != 100 {
+		t.Errorf("Expected source id 100, got: %v", id)
+	}
+	if id, _ := sourceIdField.At(1).(*int64); id != nil {
+		t.Errorf("Expected missing source id to be null, got: %v", *id)
+	}
+}
+
+func TestAlarmCountQuery(t *testing.T) {
+	var requestBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/grafana/infinity/alarm-count" {
+			t.Errorf("Unexpected upstream path: %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&requestBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":7}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarmCount",
+		`{"sourceObjectId": "2", "severityFilter": ["Major", "Critical"], "stateFilter": ["Outstanding"]}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 1 || response.Frames[0].Rows() != 1 {
+		t.Fatal("Expected single row count frame")
+	}
+	if count, _ := response.Frames[0].Fields[0].At(0).(int64); count != 7 {
+		t.Errorf("Expected count 7, got: %d", count)
+	}
+
+	if rootObjectId, _ := requestBody["rootObjectId"].(float64); rootObjectId != 2 {
+		t.Errorf("Expected rootObjectId 2 to be forwarded, got: %v", requestBody["rootObjectId"])
+	}
+	if severity, _ := requestBody["severity"].([]any); len(severity) != 2 {
+		t.Errorf("Expected severity filter to be forwarded, got: %v", requestBody["severity"])
+	}
+	if state, _ := requestBody["state"].([]any); len(state) != 1 {
+		t.Errorf("Expected state filter to be forwarded, got: %v", requestBody["state"])
+	}
+}
+
+func TestAlarmTimeFormats(t *testing.T) {
+	expected := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"RFC3339", `"2026-01-02T03:04:05Z"`},
+		{"RFC3339 with offset", `"2026-01-02T05:04:05+02:00"`},
+		{"space separated", `"2026-01-02 03:04:05"`},
+		{"epoch seconds", strconv.FormatInt(expected.Unix(), 10)},
+		{"epoch milliseconds", strconv.FormatInt(expected.UnixMilli(), 10)},
+		{"epoch seconds as string", `"` + strconv.FormatInt(expected.Unix(), 10) + `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var alarm alarmResponse
+			if err := json.Unmarshal([]byte(`{"Id":1,"Created":`+tt.raw+`,"Last Change":`+tt.raw+`}`), &alarm); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !alarm.Created.Equal(expected) || !alarm.LastChange.Equal(expected) {
+				t.Errorf("Expected %v, got: %v / %v", expected, alarm.Created.Time, alarm.LastChange.Time)
+			}
+		})
+	}
+
+	var alarm alarmResponse
+	if err := json.Unmarshal([]byte(`{"Created":"yesterday"}`), &alarm); err == nil {
+		t.Error("Expected error for unsupported time format")
+	}
+}
+
+func TestAlarmFieldSelection(t *testing.T) {
+	server := mockAlarmResponse()
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"fields": ["Message", "Id", "Severity", "Source"]}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	names := make([]string, 0, len(response.Frames[0].Fields))
+	for _, field := range response.Frames[0].Fields {
+		names = append(names, field.Name)
+	}
+	if strings.Join(names, ",") != "Id,Severity,Source,Message" {
+		t.Errorf("Expected only selected fields in frame order, got: %v", names)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "alarms", `{"fields": ["Id", "Priority"]}`)
+	if response.Error == nil {
+		t.Error("Expected error for unknown alarm field")
+	}
+}
+
+func TestAlarmLogFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":7,"Severity":"Major","State":"Outstanding","Source":"node1","Message":"Link down",` +
+			`"Created":"2026-01-01T00:00:00Z","Last Change":"2026-01-01T00:10:00Z"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"logFormat": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Meta == nil || frame.Meta.Type != data.FrameTypeLogLines || frame.Meta.PreferredVisualization != data.VisTypeLogs {
+		t.Fatalf("Expected log lines frame, got meta: %+v", frame.Meta)
+	}
+
+	expected := []struct {
+		name      string
+		fieldType data.FieldType
+	}{
+		{"timestamp", data.FieldTypeTime},
+		{"body", data.FieldTypeString},
+		{"severity", data.FieldTypeString},
+		{"id", data.FieldTypeString},
+		{"labels", data.FieldTypeJSON},
+	}
+	if len(frame.Fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got: %d", len(expected), len(frame.Fields))
+	}
+	for i, e := range expected {
+		if frame.Fields[i].Name != e.name || frame.Fields[i].Type() != e.fieldType {
+			t.Errorf("Expected field %d to be %s (%s), got: %s (%s)", i, e.name, e.fieldType, frame.Fields[i].Name, frame.Fields[i].Type())
+		}
+	}
+
+	if body, _ := frame.Fields[1].At(0).(string); body != "Link down" {
+		t.Errorf("Expected alarm message as log line, got: %q", body)
+	}
+	if level, _ := frame.Fields[2].At(0).(string); level != "error" {
+		t.Errorf("Expected Major severity mapped to error level, got: %q", level)
+	}
+	labels, _ := frame.Fields[4].At(0).(json.RawMessage)
+	var labelMap map[string]string
+	if err := json.Unmarshal(labels, &labelMap); err != nil || labelMap["severity"] != "Major" || labelMap["source"] != "node1" {
+		t.Errorf("Unexpected labels: %s", labels)
+	}
+}
+
+func TestAlarmSeverityLevel(t *testing.T) {
+	frame := buildAlarmFrame([]alarmResponse{
+		{Id: 1, Severity: "Normal"},
+		{Id: 2, Severity: "Critical"},
+		{Id: 3, Severity: "Bogus"},
+	}, "", time.Now())
+
+	severity, _ := frame.FieldByName("Severity")
+	level, _ := frame.FieldByName("Severity Level")
+	if severity == nil || level == nil {
+		t.Fatal("Expected both string and numeric severity fields")
+	}
+	for i, expected := range []int32{0, 4} {
+		value, _ := level.At(i).(*int32)
+		if value == nil || *value != expected {
+			t.Errorf("Expected level %d for %v, got: %v", expected, severity.At(i), value)
+		}
+	}
+	if value, _ := level.At(2).(*int32); value != nil {
+		t.Errorf("Expected null level for unknown severity, got: %d", *value)
+	}
+}
+
+func TestWrappedAlarmResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"alarms":[{"Id":1,"Severity":"Major","Message":"Link down"},{"Id":2,"Severity":"Minor","Message":"High load"}],"total":25}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 2 {
+		t.Fatalf("Expected 2 alarms, got: %d", frame.Rows())
+	}
+	if frame.Meta == nil || len(frame.Meta.Stats) != 1 || frame.Meta.Stats[0].Value != 25 {
+		t.Errorf("Expected total 25 in frame metadata, got: %+v", frame.Meta)
+	}
+}
+
+func TestWrappedAlarmResponseWithoutAlarms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"reason":"Access denied"}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`)
+	if response.Error == nil || !strings.Contains(response.Error.Error(), "alarms") {
+		t.Errorf("Expected error for object without alarm list, got: %v", response.Error)
+	}
+}
+
+func TestAlarmGroupBySource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Id":1,"Severity":"Minor","Source":"node1","Source Id":10,"Count":2},` +
+			`{"Id":2,"Severity":"Critical","Source":"node1","Source Id":10,"Count":1},` +
+			`{"Id":3,"Severity":"Warning","Source":"node2","Source Id":20,"Count":5},` +
+			`{"Id":4,"Severity":"Major","Source":"node1","Source Id":10,"Count":3}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"groupBySource": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 2 {
+		t.Fatalf("Expected 2 grouped rows, got: %d", frame.Rows())
+	}
+	row := func(name string, i int) any {
+		field, _ := frame.FieldByName(name)
+		return field.At(i)
+	}
+	if row("Source", 0) != "node1" || row("Severity", 0) != "Critical" || row("Alarms", 0) != int64(3) || row("Count", 0) != int64(6) {
+		t.Errorf("Unexpected node1 row: %v %v %v %v", row("Source", 0), row("Severity", 0), row("Alarms", 0), row("Count", 0))
+	}
+	if row("Source", 1) != "node2" || row("Severity", 1) != "Warning" || row("Alarms", 1) != int64(1) || row("Count", 1) != int64(5) {
+		t.Errorf("Unexpected node2 row: %v %v %v %v", row("Source", 1), row("Severity", 1), row("Alarms", 1), row("Count", 1))
+	}
+}
+
+func TestAlarmAckByFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":1,"Ack/Resolve by":"admin"},{"Id":2,"Ack/Resolve by":"operator"},{"Id":3,"Ack/Resolve by":""},{"Id":4,"Ack/Resolve by":"Admin"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"ackByFilter": "admin"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	ids, _ := response.Frames[0].FieldByName("Id")
+	if ids.Len() != 2 || ids.At(0).(int32) != 1 || ids.At(1).(int32) != 4 {
+		t.Errorf("Expected alarms 1 and 4 acknowledged by admin, got %d alarms", ids.Len())
+	}
+}
+
+func TestAlarmHistoryTransitions(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Time":1700000300,"Alarm Id":1,"Severity":"Critical","State":"Outstanding","Source":"node1","Source Id":10},` +
+			`{"Time":1700000000,"Alarm Id":1,"Severity":"Minor","State":"Outstanding","Source":"node1","Source Id":10},` +
+			`{"Time":1700000100,"Alarm Id":1,"Severity":"Minor","State":"Outstanding","Source":"node1","Source Id":10},` +
+			`{"Time":1700000200,"Alarm Id":2,"Severity":"Warning","State":"Outstanding","Source":"node2"},` +
+			`{"Time":1700000400,"Alarm Id":1,"Severity":"Critical","State":"Acknowledged","Source":"node1","Source Id":10}]`))
+	}))
+	defer server.Close()
+
+	from := time.Unix(1700000000, 0)
+	to := time.Unix(1700003600, 0)
+	ds := &NetXMSDatasource{}
+	settings := newTestSettings(server.URL)
+	response := ds.alarmHistoryQuery(context.Background(), backend.PluginContext{DataSourceInstanceSettings: &settings},
+		backend.TimeRange{From: from, To: to}, queryModel{SourceObjectId: "10"})
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if query.Get("timeFrom") != "2023-11-14T22:13:20" || query.Get("timeTo") != "2023-11-14T23:13:20" || query.Get("rootObjectId") != "10" {
+		t.Errorf("Unexpected request parameters: %v", query)
+	}
+
+	frame := response.Frames[0]
+	if frame.Name != "alarm-transitions" {
+		t.Errorf("Expected frame alarm-transitions, got: %s", frame.Name)
+	}
+	if frame.Rows() != 4 {
+		t.Fatalf("Expected 4 transitions without repeated status, got: %d", frame.Rows())
+	}
+	column := func(name string) *data.Field {
+		field, _ := frame.FieldByName(name)
+		return field
+	}
+	expected := []struct {
+		time     int64
+		id       int32
+		severity string
+		previous *string
+		state    string
+	}{
+		{1700000000, 1, "Minor", nil, "Outstanding"},
+		{1700000200, 2, "Warning", nil, "Outstanding"},
+		{1700000300, 1, "Critical", ptrTo("Minor"), "Outstanding"},
+		{1700000400, 1, "Critical", ptrTo("Critical"), "Acknowledged"},
+	}
+	for i, e := range expected {
+		previous := column("Previous Severity").At(i).(*string)
+		if column("Time").At(i).(time.Time).Unix() != e.time || column("Alarm Id").At(i) != e.id ||
+			column("Severity").At(i) != e.severity || column("State").At(i) != e.state ||
+			(previous == nil) != (e.previous == nil) || (previous != nil && *previous != *e.previous) {
+			t.Errorf("Unexpected transition %d: %v %v %v %v %v", i, column("Time").At(i), column("Alarm Id").At(i),
+				column("Severity").At(i), previous, column("State").At(i))
+		}
+	}
+	if level := column("Severity Level").At(2).(*int32); level == nil || *level != 4 {
+		t.Errorf("Expected severity level 4 for Critical, got: %v", level)
+	}
+}
+
+func TestAcknowledgeAlarmsBatch(t *testing.T) {
+	var mu sync.Mutex
+	var acknowledged []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		acknowledged = append(acknowledged, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/v1/alarms/2/acknowledge" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"reason":"Alarm not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "allowAlarmAcknowledge": true}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+	editor := &backend.User{Login: "editor", Role: "Editor"}
+	response := callTestResourceAs(t, settings, editor, http.MethodPost, "acknowledgeAlarms", []byte(`{"alarmIds":[1,2,3]}`))
+	if response.Status != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d %s", response.Status, string(response.Body))
+	}
+	var result struct {
+		Results []acknowledgeResult `json:"results"`
+	}
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	expected := []acknowledgeResult{
+		{Id: 1, Success: true},
+		{Id: 2, Success: false, Error: "Request error: Alarm not found"},
+		{Id: 3, Success: true},
+	}
+	if !slices.Equal(result.Results, expected) {
+		t.Errorf("Expected results %+v, got: %+v", expected, result.Results)
+	}
+	if len(acknowledged) != 3 {
+		t.Errorf("Expected acknowledgement request for each alarm, got: %v", acknowledged)
+	}
+
+	response = callTestResourceAs(t, settings, editor, http.MethodPost, "acknowledgeAlarms", []byte(`{"alarmIds":[]}`))
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for empty batch, got: %d", response.Status)
+	}
+}
+
+func TestAcknowledgeAlarmsAuthorization(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	enabled := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "allowAlarmAcknowledge": true}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+	tests := []struct {
+		name     string
+		settings backend.DataSourceInstanceSettings
+		user     *backend.User
+		status   int
+	}{
+		{"disabled by default", newTestSettings(server.URL), &backend.User{Role: "Admin"}, http.StatusForbidden},
+		{"viewer", enabled, &backend.User{Role: "Viewer"}, http.StatusForbidden},
+		{"no user", enabled, nil, http.StatusForbidden},
+		{"editor", enabled, &backend.User{Role: "Editor"}, http.StatusOK},
+		{"admin", enabled, &backend.User{Role: "Admin"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		requests.Store(0)
+		response := callTestResourceAs(t, tt.settings, tt.user, http.MethodPost, "acknowledgeAlarms", []byte(`{"alarmId":1}`))
+		if response.Status != tt.status {
+			t.Errorf("%s: expected status %d, got: %d %s", tt.name, tt.status, response.Status, string(response.Body))
+		}
+		if tt.status == http.StatusForbidden && requests.Load() != 0 {
+			t.Errorf("%s: expected no request to server, got: %d", tt.name, requests.Load())
+		}
+	}
+}
+
+func TestAlarmWithDciValues(t *testing.T) {
+	var lookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/grafana/infinity/alarms":
+			_, _ = w.Write([]byte(`[` +
+				`{"Id":1,"Severity":"Major","Source":"node1","Source Id":10,"DCI Id":100},` +
+				`{"Id":2,"Severity":"Minor","Source":"node2","Source Id":20},` +
+				`{"Id":3,"Severity":"Warning","Source":"node1","Source Id":10,"DCI Id":100},` +
+				`{"Id":4,"Severity":"Critical","Source":"node3","Source Id":30,"DCI Id":300}]`))
+		case "/v1/objects/10/data-collection/100/history":
+			lookups.Add(1)
+			if r.URL.Query().Get("itemCount") != "1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"values":[{"timestamp":"2024-01-01T00:00:00Z","value":"97.5"}]}`))
+		default:
+			lookups.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"withDciValues": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	field, _ := response.Frames[0].FieldByName("DCI Value")
+	if field == nil {
+		t.Fatal("Expected DCI Value column")
+	}
+	expected := []*string{ptrTo("97.5"), nil, ptrTo("97.5"), nil}
+	for i, e := range expected {
+		value := field.At(i).(*string)
+		if (value == nil) != (e == nil) || value != nil && *value != *e {
+			t.Errorf("Unexpected DCI value of alarm %d: %v", i+1, value)
+		}
+	}
+	if lookups.Load() != 2 {
+		t.Errorf("Expected single lookup per distinct DCI, got: %d", lookups.Load())
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`)
+	if field, _ := response.Frames[0].FieldByName("DCI Value"); field != nil {
+		t.Error("Expected no DCI Value column by default")
+	}
+}
+
+func TestAlarmAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	frame := buildAlarmFrame([]alarmResponse{
+		{Id: 1, Created: alarmTime{now.Add(-90 * time.Minute)}},
+		{Id: 2},
+		{Id: 3, Created: alarmTime{now.Add(time.Minute)}},
+	}, "", now)
+
+	age, _ := frame.FieldByName("Age")
+	if age == nil || age.Config == nil || age.Config.Unit != "s" {
+		t.Fatalf("Expected Age field in seconds, got: %v", age)
+	}
+	if value := age.At(0).(*int64); value == nil || *value != 5400 {
+		t.Errorf("Expected age of 5400 seconds, got: %v", value)
+	}
+	if value := age.At(1).(*int64); value != nil {
+		t.Errorf("Expected null age for alarm without creation time, got: %v", *value)
+	}
+	if value := age.At(2).(*int64); value == nil || *value != 0 {
+		t.Errorf("Expected zero age for alarm created in future, got: %v", value)
+	}
+}
+
+func TestAlarmFieldOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":1,"Severity":"Major","State":"Outstanding","Source":"node1","Message":"Link down"}]`))
+	}))
+	defer server.Close()
+
+	names := func(frame *data.Frame) []string {
+		result := make([]string, len(frame.Fields))
+		for i, field := range frame.Fields {
+			result[i] = field.Name
+		}
+		return result
+	}
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"fieldOrder": ["Severity", "Message", "Severity", "Bogus"]}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	expected := []string{"Severity", "Message", "Id", "Severity Level", "State", "Source", "Source Id",
+		"Count", "Ack/Resolve by", "Created", "Last Change", "Age"}
+	if got := names(response.Frames[0]); !slices.Equal(got, expected) {
+		t.Errorf("Expected field order %v, got: %v", expected, got)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "alarms", `{"fields": ["Id", "Source", "Message"], "fieldOrder": ["Message", "Severity"]}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if got := names(response.Frames[0]); !slices.Equal(got, []string{"Message", "Id", "Source"}) {
+		t.Errorf("Expected selected fields reordered, got: %v", got)
+	}
+}
@@ -4,18 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
-	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/raden-solutions/net-xms/pkg/models"
@@ -38,11 +43,30 @@ var (
 type NetXMSDatasource struct {
 	queryHandler    backend.QueryDataHandler
 	resourceHandler backend.CallResourceHandler
+	cache           *responseCache
+	requestSlots    chan struct{}     // limits concurrent upstream requests, nil if unlimited
+	transport       http.RoundTripper // shared transport with connect timeout, default transport if nil
+	gzipAccepted    atomic.Bool       // server advertised support of gzip encoded request bodies
+	slowResponse    time.Duration     // query time after which slow response notice is shown, 0 disables notice
+	failureMode     string            // result of failed queries, error if empty
+	maxQueries      int               // maximum number of queries processed per request, 0 if unlimited
 }
 
 // NewDatasource creates a new NetXMS datasource instance
-func NewDatasource(_ context.Context, _ backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
-	ds := &NetXMSDatasource{}
+func NewDatasource(_ context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+	// Invalid settings are reported by queries and health check, defaults are used meanwhile
+	config, err := models.LoadPluginSettings(settings)
+	if err != nil {
+		config = &models.PluginSettings{}
+	}
+	ds := &NetXMSDatasource{
+		cache:        newResponseCache(),
+		requestSlots: make(chan struct{}, config.MaxConcurrentRequests()),
+		transport:    newTransport(config),
+		slowResponse: config.SlowResponseWarning(),
+		failureMode:  config.FailureMode,
+		maxQueries:   config.MaxQueriesPerRequest(),
+	}
 	mux := http.NewServeMux()
 	mux.HandleFunc("/alarmObjects", ds.handleAlarmObjects)
 	mux.HandleFunc("/dciObjects", ds.handleDciObjects)
@@ -51,13 +75,24 @@ func NewDatasource(_ context.Context, _ backend.DataSourceInstanceSettings) (ins
 	mux.HandleFunc("/summaryTableObjects", ds.handleSummaryTableObjects)
 	mux.HandleFunc("/summaryTables", ds.handleSummaryTables)
 	mux.HandleFunc("/dcis", ds.handleDciList)
+	mux.HandleFunc("/dciType", ds.handleDciType)
+	mux.HandleFunc("/version", ds.handleVersion)
+	mux.HandleFunc("/interfaces", ds.handleInterfaces)
+	mux.HandleFunc("/previewTable", ds.handlePreviewTable)
+	mux.HandleFunc("/config", ds.handleConfig)
+	mux.HandleFunc("/resolveObject", ds.handleResolveObject)
+	mux.HandleFunc("/acknowledgeAlarms", ds.handleAcknowledgeAlarms)
 	ds.resourceHandler = httpadapter.New(mux)
 	queryTypeMux := datasource.NewQueryTypeMux()
 	queryTypeMux.HandleFunc("alarms", ds.handleAlarmQuery)
+	queryTypeMux.HandleFunc("alarmCount", ds.handleAlarmCountQuery)
+	queryTypeMux.HandleFunc("alarmHistory", ds.handleAlarmHistoryQuery)
 	queryTypeMux.HandleFunc("dciValues", ds.handleDciValues)
 	queryTypeMux.HandleFunc("summaryTables", ds.handleSummaryTableQuery)
 	queryTypeMux.HandleFunc("objectQueries", ds.handleObjectQueryQuery)
 	queryTypeMux.HandleFunc("objectStatus", ds.handleObjectStatusQuery)
+	queryTypeMux.HandleFunc("objectChildren", ds.handleObjectChildrenQuery)
+	queryTypeMux.HandleFunc("serverInfo", ds.handleServerInfoQuery)
 	ds.queryHandler = queryTypeMux
 	return ds, nil
 }
@@ -65,202 +100,244 @@ func NewDatasource(_ context.Context, _ backend.DataSourceInstanceSettings) (ins
 // Dispose here tells plugin SDK that plugin wants to clean up resources when a new instance
 // created. As soon as datasource settings change detected by SDK old datasource instance will
 // be disposed and a new one will be created using NewSampleDatasource factory function.
-func (d *NetXMSDatasource) Dispose() {}
+func (d *NetXMSDatasource) Dispose() {
+	if transport, ok := d.transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+}
 
 type queryModel struct {
-	SourceObjectId string `json:"sourceObjectId"`
-	DciId          string `json:"dciId"`
+	SourceObjectId     queryID  `json:"sourceObjectId"`
+	DciId              queryID  `json:"dciId"`
+	AllowEmptyResponse bool     `json:"allowEmptyResponse"`
+	ServerAggregation  string   `json:"serverAggregation"`
+	RawJSON            bool     `json:"rawJson"`
+	Instance           string   `json:"instance"`
+	MessageFilter      string   `json:"messageFilter"`
+	MessageFilterRegex bool     `json:"messageFilterRegex"`
+	AckByFilter        string   `json:"ackByFilter"`
+	StatusFilter       []string `json:"statusFilter"`
+	StatusFilterMode   string   `json:"statusFilterMode"`
+	CountHistory       bool     `json:"countHistory"`
+	AllDcis            bool     `json:"allDcis"`
+	SeverityFilter     []string `json:"severityFilter"`
+	StateFilter        []string `json:"stateFilter"`
+	Fields             []string `json:"fields"`
+	Depth              int      `json:"depth"`
+	LogFormat          bool     `json:"logFormat"`
+	StatusSummary      bool     `json:"statusSummary"`
+	Histogram          bool     `json:"histogram"`
+	Buckets            int      `json:"buckets"`
+	GroupBySource      bool     `json:"groupBySource"`
+	LastValue          bool     `json:"lastValue"`
+	MaxFrames          int      `json:"maxFrames"`
+	Derivative         string   `json:"derivative"`
+	Percentile         *float64 `json:"percentile"`
+	IntervalMs         int64    `json:"intervalMs"`
+	WithDciValues      bool     `json:"withDciValues"`
+	WithStatusSummary  bool     `json:"withStatusSummary"`
+	FieldOrder         []string `json:"fieldOrder"`
+	MaxPoints          int      `json:"maxPoints"`
+	SeriesNameFrom     string   `json:"seriesNameFrom"`
+	SeriesName         string   `json:"seriesName"`
+	SeriesNameLabel    string   `json:"seriesNameLabel"`
+}
+
+// queryID is object or DCI id in query JSON, which editors may encode either as string or as number
+type queryID string
+
+func (id *queryID) UnmarshalJSON(raw []byte) error {
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		// Ids which are not numeric are kept as is and reported by query validation
+		if num, err := parseID(str); err == nil {
+			str = strconv.FormatInt(num, 10)
+		}
+		*id = queryID(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(raw, &num); err != nil {
+		return fmt.Errorf("id must be string or number: %w", err)
+	}
+	parsed, err := parseID(num.String())
+	if err != nil {
+		return err
+	}
+	*id = queryID(strconv.FormatInt(parsed, 10))
+	return nil
 }
 
-type alarmResponse struct {
-	Id         int32     `json:"Id"`
-	Severity   string    `json:"Severity"`
-	State      string    `json:"State"`
-	Source     string    `json:"Source"`
-	Message    string    `json:"Message"`
-	Count      int32     `json:"Count"`
-	AckBy      string    `json:"Ack/Resolve by"`
-	Created    time.Time `json:"Created"`
-	LastChange time.Time `json:"Last Change"`
+// parseID parses numeric id, accepting also exponent notation (1.23e9) which
+// may be produced for large ids encoded as JSON numbers. Ids with fractional
+// part or out of int64 range are rejected.
+func parseID(str string) (int64, error) {
+	if id, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return id, nil
+	}
+	num, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", str)
+	}
+	if num != math.Trunc(num) {
+		return 0, fmt.Errorf("invalid id %q: fractional part is not allowed", str)
+	}
+	if num < math.MinInt64 || num >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid id %q: out of range", str)
+	}
+	return int64(num), nil
 }
 
-type dciValueResponse struct {
-	Description string `json:"description"`
-	UnitName    string `json:"unitName"`
-	Values      []struct {
-		Timestamp string `json:"timestamp"`
-		Value     string `json:"value"`
-	} `json:"values"`
+func (d *NetXMSDatasource) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	limited := *req
+	if d.maxQueries > 0 && len(req.Queries) > d.maxQueries {
+		limited.Queries = req.Queries[:d.maxQueries]
+	}
+
+	resp, err := d.queryHandler.QueryData(ctx, &limited)
+	if err != nil {
+		return resp, fmt.Errorf("query data: %w", err)
+	}
+	for _, q := range req.Queries[len(limited.Queries):] {
+		resp.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest,
+			fmt.Sprintf("too many queries in request, at most %d are processed", d.maxQueries))
+	}
+	if d.failureMode == models.FailureModeEmpty {
+		for refID, res := range resp.Responses {
+			if res.Error != nil {
+				resp.Responses[refID] = emptyFailureResponse(res)
+			}
+		}
+	}
+	return resp, nil
 }
 
-type requiredField struct {
-	field   string
-	message string
+// emptyFailureResponse replaces failed query response with empty frame, so
+// failure doesn't affect panel visually. Error is kept as frame notice.
+func emptyFailureResponse(res backend.DataResponse) backend.DataResponse {
+	frame := data.NewFrame("")
+	frame.AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: res.Error.Error()})
+	return backend.DataResponse{Frames: data.Frames{frame}}
 }
 
-type tableQueryConfig struct {
-	url        string
-	frameName  string
-	required   []requiredField
-	formatBody func(map[string]any) (map[string]any, error)
+// timeQuery runs single query and attaches notice about slow response to its
+// frames if it took longer than configured threshold
+func (d *NetXMSDatasource) timeQuery(query func() backend.DataResponse) backend.DataResponse {
+	start := time.Now()
+	res := query()
+	if elapsed := time.Since(start); d.slowResponse > 0 && elapsed > d.slowResponse {
+		addSlowResponseNotice(res.Frames, elapsed)
+	}
+	return res
 }
 
-func (d *NetXMSDatasource) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	resp, err := d.queryHandler.QueryData(ctx, req)
-	if err != nil {
-		return resp, fmt.Errorf("query data: %w", err)
+// addSlowResponseNotice attaches notice about slow server response to the first frame
+func addSlowResponseNotice(frames data.Frames, elapsed time.Duration) {
+	if len(frames) == 0 {
+		return
 	}
-	return resp, nil
+	frames[0].AppendNotices(data.Notice{
+		Severity: data.NoticeSeverityInfo,
+		Text:     fmt.Sprintf("Slow response from NetXMS server: query took %s", elapsed.Round(time.Millisecond)),
+	})
 }
 
-// QueryData handles multiple queries and returns multiple responses.
-// req contains the queries []DataQuery (where each query contains RefID as a unique identifier).
-// The QueryDataResponse contains a map of RefID to the response for each query, and each response
-// contains Frames ([]*Frame).
-func (d *NetXMSDatasource) handleAlarmQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+// handleServerInfoQuery returns server information as key/value table
+func (d *NetXMSDatasource) handleServerInfoQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	response := backend.NewQueryDataResponse()
 
 	for _, q := range req.Queries {
-		var qm queryModel
-		err := json.Unmarshal(q.JSON, &qm)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
-			continue
-		}
-
-		res := d.query(ctx, req.PluginContext, qm.SourceObjectId)
-		response.Responses[q.RefID] = res
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			return d.serverInfoQuery(ctx, req.PluginContext)
+		})
 	}
 
 	return response, nil
 }
 
-func (d *NetXMSDatasource) query(ctx context.Context, pCtx backend.PluginContext, rootObjectId string) backend.DataResponse {
-	var response backend.DataResponse
+func (d *NetXMSDatasource) serverInfoQuery(ctx context.Context, pCtx backend.PluginContext) backend.DataResponse {
 	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
 	if err != nil {
 		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
 	}
+	config = config.ForQueryType("serverInfo")
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	statusURL := joinURL(config.ServerAddress, "v1/grafana/infinity/alarms")
-
-	var bodyBytes []byte
-	if rootObjectId != "" {
-		rootObjectIdNum, parseErr := strconv.ParseInt(rootObjectId, 10, 64)
-		if parseErr != nil {
-			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("invalid rootObjectId: %v", parseErr.Error()))
-		}
-		body := map[string]any{
-			"rootObjectId": rootObjectIdNum,
-		}
-		bodyBytes, err = json.Marshal(body)
-		if err != nil {
-			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err.Error()))
-		}
-	} else {
-		bodyBytes = []byte(`{}`)
-	}
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, statusURL, bytes.NewBuffer(bodyBytes))
+	infoURL := joinURL(config.ServerAddress, "v1/server-info")
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, infoURL, nil, config)
 	if err != nil {
 		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err.Error()))
 	}
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Add("Authorization", "Bearer "+config.Secrets.ApiKey)
 
-	result, err := client.Do(request)
+	result, err := d.httpClient(ctx, config).Do(request)
 	if err != nil {
 		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err.Error()))
 	}
 	defer result.Body.Close()
 
-	body, err := io.ReadAll(result.Body)
+	body, err := readResponseBody(result.Body, config)
 	if err != nil {
 		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err.Error()))
 	}
 
-	if result.StatusCode == http.StatusUnauthorized {
-		return backend.ErrDataResponse(backend.StatusUnauthorized, "Unauthorized: Invalid API key")
+	if isEmptyResponse(false, result.StatusCode, body, config) {
+		return backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("server-info",
+				data.NewField("key", nil, []string{}),
+				data.NewField("value", nil, []string{}),
+			)},
+		}
 	}
 
-	if result.StatusCode != http.StatusOK {
-		return parseErrorResponse(result.StatusCode, body)
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
 	}
 
-	var alarms []alarmResponse
-	if err := json.Unmarshal(body, &alarms); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var info map[string]any
+	if err := decoder.Decode(&info); err != nil {
 		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err.Error()))
 	}
 
-	frame := data.NewFrame("alarms")
-
-	ids := make([]int32, len(alarms))
-	severities := make([]string, len(alarms))
-	states := make([]string, len(alarms))
-	sources := make([]string, len(alarms))
-	messages := make([]string, len(alarms))
-	counts := make([]int32, len(alarms))
-	ackBy := make([]string, len(alarms))
-	created := make([]time.Time, len(alarms))
-	lastChange := make([]time.Time, len(alarms))
-
-	for i, alarm := range alarms {
-		ids[i] = alarm.Id
-		severities[i] = alarm.Severity
-		states[i] = alarm.State
-		sources[i] = alarm.Source
-		messages[i] = alarm.Message
-		counts[i] = alarm.Count
-		ackBy[i] = alarm.AckBy
-		created[i] = alarm.Created
-		lastChange[i] = alarm.LastChange
+	keys := []string{}
+	values := []string{}
+	flattenKeyValues("", info, &keys, &values)
+	return backend.DataResponse{
+		Frames: data.Frames{data.NewFrame("server-info",
+			data.NewField("key", nil, keys),
+			data.NewField("value", nil, values),
+		)},
 	}
+}
 
-	severityField := data.NewField("Severity", nil, severities)
-	severityField.Config = &data.FieldConfig{
-		Mappings: data.ValueMappings{
-			data.ValueMapper{
-				"Normal":    {Text: "Normal", Color: "rgb(0, 137, 0)"},
-				"Warning":   {Text: "Warning", Color: "rgb(0, 142, 145)"},
-				"Minor":     {Text: "Minor", Color: "rgb(201, 198, 0)"},
-				"Major":     {Text: "Major", Color: "rgb(223, 102, 0)"},
-				"Critical":  {Text: "Critical", Color: "rgb(160, 0, 0)"},
-				"Unknown":   {Text: "Unknown", Color: "rgb(33, 33, 248)"},
-				"Unmanaged": {Text: "Unmanaged", Color: "rgb(113, 113, 113)"},
-				"Disabled":  {Text: "Disabled", Color: "rgb(100, 41, 0)"},
-				"Testing":   {Text: "Testing", Color: "rgb(138, 0, 143)"},
-			},
-		},
-	}
-	stateField := data.NewField("State", nil, states)
-	stateField.Config = &data.FieldConfig{
-		Mappings: data.ValueMappings{
-			data.ValueMapper{
-				"Outstanding":  {Text: "Outstanding", Color: "yellow"},
-				"Acknowledged": {Text: "Acknowledged", Color: "greenyellow"},
-				"Resolved":     {Text: "Resolved", Color: "green"},
-			},
-		},
+// flattenKeyValues appends leaf values of decoded JSON with dotted keys
+// ("license.expires", "addresses.0"). Object keys are sorted.
+func flattenKeyValues(prefix string, value any, keys *[]string, values *[]string) {
+	childKey := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
 	}
 
-	frame.Fields = append(frame.Fields,
-		data.NewField("Id", nil, ids),
-		severityField,
-		stateField,
-		data.NewField("Source", nil, sources),
-		data.NewField("Message", nil, messages),
-		data.NewField("Count", nil, counts),
-		data.NewField("Ack/Resolve by", nil, ackBy),
-		data.NewField("Created", nil, created),
-		data.NewField("Last Change", nil, lastChange),
-	)
-
-	response.Frames = append(response.Frames, frame)
-	return response
+	switch v := value.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			flattenKeyValues(childKey(name), v[name], keys, values)
+		}
+	case []any:
+		for i, item := range v {
+			flattenKeyValues(childKey(strconv.Itoa(i)), item, keys, values)
+		}
+	default:
+		*keys = append(*keys, prefix)
+		*values = append(*values, formatTableValue(v))
+	}
 }
 
 // Compare server version
@@ -296,7 +373,7 @@ func (d *NetXMSDatasource) CheckHealth(ctx context.Context, req *backend.CheckHe
 
 	if err != nil {
 		res.Status = backend.HealthStatusError
-		res.Message = "Unable to load settings"
+		res.Message = fmt.Sprintf("Unable to load settings: %v", err)
 		return res, nil
 	}
 
@@ -312,65 +389,94 @@ func (d *NetXMSDatasource) CheckHealth(ctx context.Context, req *backend.CheckHe
 		return res, nil
 	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	statusURL := joinURL(config.ServerAddress, "v1/server-info")
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, http.NoBody)
+	actualVersion, err := d.getServerVersion(ctx, config)
 	if err != nil {
 		res.Status = backend.HealthStatusError
-		res.Message = fmt.Sprintf("Failed to create request: %v", err)
+		res.Message = err.Error()
+		return res, nil
+	}
+	if actualVersion == "" {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusOk,
+			Message: "Data source is working, server version is unknown",
+		}, nil
+	}
+	requiredVersion := "5.2.4"
+	if !isVersionGreater(actualVersion, requiredVersion) {
+		log.DefaultLogger.Warn("Server version is below required minimum", "actual", actualVersion, "required", requiredVersion)
+		res.Status = backend.HealthStatusError
+		res.Message = fmt.Sprintf("Server version (current: %s) should be equal or greater than %s", actualVersion, requiredVersion)
 		return res, nil
 	}
 
-	request.Header.Add("Authorization", "Bearer "+config.Secrets.ApiKey)
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
+
+// dnsRetryDelay is time waited before health check request failed with
+// temporary resolver error is repeated
+const dnsRetryDelay = 250 * time.Millisecond
+
+// isTransientDNSError checks if resolver failure may succeed when repeated,
+// unknown hosts are reported immediately
+func isTransientDNSError(err *net.DNSError) bool {
+	return !err.IsNotFound && (err.IsTimeout || err.IsTemporary)
+}
+
+// getServerVersion requests server information and returns NetXMS server version
+func (d *NetXMSDatasource) getServerVersion(ctx context.Context, config *models.PluginSettings) (string, error) {
+	client := d.httpClient(ctx, config)
+
+	statusURL := joinURL(config.ServerAddress, "v1/server-info")
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, statusURL, nil, config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
 
 	response, err := client.Do(request)
+	var dnsErr *net.DNSError
+	if err != nil && errors.As(err, &dnsErr) && isTransientDNSError(dnsErr) {
+		// Temporary resolver failures are retried once after short delay before reporting
+		timer := time.NewTimer(dnsRetryDelay)
+		select {
+		case <-timer.C:
+			response, err = client.Do(request)
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
 	if err != nil {
-		res.Status = backend.HealthStatusError
-		res.Message = fmt.Sprintf("Failed to connect to server: %v", err)
-		return res, nil
+		if errors.As(err, &dnsErr) {
+			return "", fmt.Errorf("hostname could not be resolved: %s", dnsErr.Name)
+		}
+		return "", fmt.Errorf("failed to connect to server: %w", err)
 	}
 	defer response.Body.Close()
 
-	body, err := io.ReadAll(response.Body)
+	body, err := readResponseBody(response.Body, config)
 	if err != nil {
-		res.Status = backend.HealthStatusError
-		res.Message = fmt.Sprintf("failed to read response: %d (%s)", response.StatusCode, response.Status)
-		return res, nil
+		return "", fmt.Errorf("failed to read response: %d (%s)", response.StatusCode, response.Status)
 	}
 
-	if response.StatusCode != http.StatusOK {
-		res.Status = backend.HealthStatusError
-		res.Message = fmt.Sprintf("Server returned status code: %d (%s)", response.StatusCode, response.Status)
-		return res, nil
+	if !config.IsHealthyStatus(response.StatusCode) {
+		return "", fmt.Errorf("server returned status code: %d (%s)", response.StatusCode, response.Status)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		// Gateways configured to answer with empty healthy response don't report version
+		return "", nil
 	}
 
 	var serverInfo map[string]any
 	if err := json.Unmarshal(body, &serverInfo); err != nil {
-		res.Status = backend.HealthStatusError
-		res.Message = fmt.Sprintf("Failed to parse server response: %v", err)
-		return res, nil
+		return "", fmt.Errorf("failed to parse server response: %w", err)
 	}
-	actualVersion, ok := serverInfo["version"].(string)
+	version, ok := serverInfo["version"].(string)
 	if !ok {
-		res.Status = backend.HealthStatusError
-		res.Message = "Server response missing version field"
-		return res, nil
+		return "", errors.New("server response missing version field")
 	}
-	requiredVersion := "5.2.4"
-	if !isVersionGreater(actualVersion, requiredVersion) {
-		log.DefaultLogger.Warn("Server version is below required minimum", "actual", actualVersion, "required", requiredVersion)
-		res.Status = backend.HealthStatusError
-		res.Message = fmt.Sprintf("Server version (current: %s) should be equal or greater than %s", actualVersion, requiredVersion)
-		return res, nil
-	}
-
-	return &backend.CheckHealthResult{
-		Status:  backend.HealthStatusOk,
-		Message: "Data source is working",
-	}, nil
+	return version, nil
 }
 
 func (ds *NetXMSDatasource) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
@@ -382,40 +488,17 @@ func (ds *NetXMSDatasource) CallResource(ctx context.Context, req *backend.CallR
 
 // This method handles all request to get lists of items in format name : id
 func (ds *NetXMSDatasource) handleQuery(url string, rw http.ResponseWriter, req *http.Request) {
-	pCtx := backend.PluginConfigFromContext(req.Context())
-	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
-	if err != nil {
-		http.Error(rw, "failed to load plugin settings", http.StatusInternalServerError)
-		return
-	}
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	statusURL := joinURL(config.ServerAddress, url)
-	request, err := http.NewRequestWithContext(req.Context(), http.MethodGet, statusURL, http.NoBody)
-	if err != nil {
-		http.Error(rw, "failed to create request", http.StatusInternalServerError)
-		return
-	}
-
-	request.Header.Add("Authorization", "Bearer "+config.Secrets.ApiKey)
-
-	result, err := client.Do(request)
-	if err != nil {
-		http.Error(rw, "failed to connect to server", http.StatusInternalServerError)
+	config, body, ok := ds.fetchResource(url, rw, req)
+	if !ok {
 		return
 	}
-	defer result.Body.Close()
 
-	body, err := io.ReadAll(result.Body)
-	if err != nil {
-		http.Error(rw, "failed to read response", http.StatusInternalServerError)
+	if !config.SortObjects && !config.ObjectPaths {
+		writeJSONResponse(rw, body)
 		return
 	}
 
-	// Parse JSON and sort by label
+	// Parse JSON to update labels and sort by them
 	var responseData map[string]any
 	if unmarshalErr := json.Unmarshal(body, &responseData); unmarshalErr != nil {
 		writeJSONResponse(rw, body)
@@ -438,17 +521,22 @@ func (ds *NetXMSDatasource) handleQuery(url string, rw http.ResponseWriter, req
 			return
 		}
 		jsonData[i] = objMap
+		if config.ObjectPaths {
+			addObjectPath(objMap)
+		}
 	}
 
 	// Sort by name field
-	sort.Slice(jsonData, func(i, j int) bool {
-		nameI, okI := jsonData[i]["name"].(string)
-		nameJ, okJ := jsonData[j]["name"].(string)
-		if !okI || !okJ {
-			return false
-		}
-		return nameI < nameJ
-	})
+	if config.SortObjects {
+		sort.Slice(jsonData, func(i, j int) bool {
+			nameI, okI := jsonData[i]["name"].(string)
+			nameJ, okJ := jsonData[j]["name"].(string)
+			if !okI || !okJ {
+				return false
+			}
+			return naturalLess(nameI, nameJ)
+		})
+	}
 
 	// Update the objects field with sorted data
 	responseData["objects"] = jsonData
@@ -456,622 +544,164 @@ func (ds *NetXMSDatasource) handleQuery(url string, rw http.ResponseWriter, req
 	// Marshal back to JSON
 	sortedBody, err := json.Marshal(responseData)
 	if err != nil {
-		http.Error(rw, "failed to marshal sorted response", http.StatusInternalServerError)
+		writeJSONError(rw, http.StatusInternalServerError, "failed to marshal sorted response")
 		return
 	}
 
 	writeJSONResponse(rw, sortedBody)
 }
 
-func (ds *NetXMSDatasource) handleAlarmObjects(rw http.ResponseWriter, req *http.Request) {
-	ds.handleQuery("/v1/grafana/object-list?filter=alarm", rw, req)
-}
-
-func (ds *NetXMSDatasource) handleDciObjects(rw http.ResponseWriter, req *http.Request) {
-	ds.handleQuery("/v1/grafana/object-list?filter=dci", rw, req)
-}
-
-func (ds *NetXMSDatasource) handleSummaryTables(rw http.ResponseWriter, req *http.Request) {
-	ds.handleQuery("/v1/grafana/summary-table-list", rw, req)
-}
-
-func (ds *NetXMSDatasource) handleSummaryTableObjects(rw http.ResponseWriter, req *http.Request) {
-	ds.handleQuery("/v1/grafana/object-list?filter=summary", rw, req)
-}
-
-func (ds *NetXMSDatasource) handleObjectQueries(rw http.ResponseWriter, req *http.Request) {
-	ds.handleQuery("/v1/grafana/query-list", rw, req)
-}
-
-func (ds *NetXMSDatasource) handleObjectQueryObjects(rw http.ResponseWriter, req *http.Request) {
-	ds.handleQuery("/v1/grafana/object-list?filter=query", rw, req)
-}
-
-func (ds *NetXMSDatasource) handleDciList(rw http.ResponseWriter, req *http.Request) {
-	objectID := req.URL.Query().Get("objectId")
-	if objectID == "" {
-		http.Error(rw, "missing objectId parameter", http.StatusBadRequest)
-		return
-	}
-	if _, err := strconv.ParseInt(objectID, 10, 64); err != nil {
-		http.Error(rw, "objectId must be numeric", http.StatusBadRequest)
-		return
+// fetchResource requests given server path for resource call, writing error
+// response if settings can't be loaded or request fails
+func (ds *NetXMSDatasource) fetchResource(url string, rw http.ResponseWriter, req *http.Request) (*models.PluginSettings, []byte, bool) {
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, fmt.Sprintf("failed to load plugin settings: %v", err))
+		return nil, nil, false
 	}
-	path := fmt.Sprintf("/v1/grafana/objects/%s/dci-list", objectID)
-	ds.handleQuery(path, rw, req)
-}
-
-func (ds *NetXMSDatasource) handleDciValues(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	response := backend.NewQueryDataResponse()
-	for _, q := range req.Queries {
-		var qm queryModel
-		if err := json.Unmarshal(q.JSON, &qm); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
-			continue
-		}
-
-		if _, err := strconv.ParseInt(qm.SourceObjectId, 10, 64); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, "sourceObjectId must be numeric")
-			continue
-		}
-		if _, err := strconv.ParseInt(qm.DciId, 10, 64); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, "dciId must be numeric")
-			continue
-		}
-
-		config, err := models.LoadPluginSettings(*req.PluginContext.DataSourceInstanceSettings)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
-			continue
-		}
-
-		client := &http.Client{
-			Timeout: 10 * time.Second,
-		}
-
-		timeFrom := q.TimeRange.From.Unix()
-		timeTo := q.TimeRange.To.Unix()
-
-		url := joinURL(config.ServerAddress, fmt.Sprintf("v1/objects/%s/data-collection/%s/history?timeFrom=%d&timeTo=%d",
-			qm.SourceObjectId, qm.DciId, timeFrom, timeTo))
-
-		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
-			continue
-		}
-
-		request.Header.Add("Authorization", "Bearer "+config.Secrets.ApiKey)
-
-		result, err := client.Do(request)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
-			continue
-		}
-
-		body, err := io.ReadAll(result.Body)
-		result.Body.Close()
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
-			continue
-		}
 
-		if result.StatusCode == http.StatusUnauthorized {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusUnauthorized, "Unauthorized: Invalid API key")
-			continue
-		}
-
-		if result.StatusCode != http.StatusOK {
-			response.Responses[q.RefID] = parseErrorResponse(result.StatusCode, body)
-			continue
-		}
-
-		var dciData dciValueResponse
-		if err := json.Unmarshal(body, &dciData); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
-			continue
-		}
-
-		frame := data.NewFrame(dciData.Description)
-
-		times := make([]time.Time, len(dciData.Values))
-
-		// First, try to parse all values as floats
-		isNumeric := true
-		floatValues := make([]float64, len(dciData.Values))
-		var parseError error
-
-		for i, v := range dciData.Values {
-			t, err := time.Parse(time.RFC3339, v.Timestamp)
-			if err != nil {
-				parseError = fmt.Errorf("failed to parse timestamp: %w", err)
-				break
-			}
-			times[i] = t
-
-			val, err := strconv.ParseFloat(v.Value, 64)
-			if err != nil {
-				isNumeric = false
-			} else {
-				floatValues[i] = val
-			}
-		}
-
-		if parseError != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, parseError.Error())
-			continue
-		}
-
-		if isNumeric {
-			// All values are numeric, use float64 field
-			frame.Fields = append(frame.Fields,
-				data.NewField("time", nil, times),
-				data.NewField("value", map[string]string{"unit": dciData.UnitName}, floatValues),
-			)
-		} else {
-			// Some values are not numeric, use string field
-			stringValues := make([]string, len(dciData.Values))
-			for i, v := range dciData.Values {
-				stringValues[i] = v.Value
-			}
-			frame.Fields = append(frame.Fields,
-				data.NewField("time", nil, times),
-				data.NewField("value", nil, stringValues),
-			)
-		}
-
-		response.Responses[q.RefID] = backend.DataResponse{
-			Frames: data.Frames{frame},
-		}
-	}
-	return response, nil
-}
+	client := ds.httpClient(req.Context(), config)
 
-func decodeJSONKeyOrder(rawData []byte) ([]string, error) {
-	var raw json.RawMessage
-	if err := json.Unmarshal(rawData, &raw); err != nil {
-		return nil, fmt.Errorf("unmarshal raw JSON: %w", err)
+	statusURL := joinURL(config.ServerAddress, url)
+	request, err := newAuthenticatedRequest(req.Context(), http.MethodGet, statusURL, nil, config)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to create request")
+		return nil, nil, false
 	}
 
-	dec := json.NewDecoder(bytes.NewReader(raw))
-	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
-		return nil, fmt.Errorf("expected object, got %v", token)
+	result, err := client.Do(request)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to connect to server")
+		return nil, nil, false
 	}
+	defer result.Body.Close()
 
-	var keys []string
-	for dec.More() {
-		key, err := dec.Token()
-		if err != nil {
-			return nil, fmt.Errorf("read key token: %w", err)
-		}
-		keyStr, ok := key.(string)
-		if !ok {
-			return nil, fmt.Errorf("expected string key, got %v", key)
-		}
-
-		var value any
-		if err := dec.Decode(&value); err != nil {
-			return nil, fmt.Errorf("decode value for key %q: %w", keyStr, err)
-		}
-
-		keys = append(keys, keyStr)
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to read response")
+		return nil, nil, false
 	}
 
-	if _, err := dec.Token(); err != nil {
-		return nil, fmt.Errorf("read closing token: %w", err)
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		writeJSONError(rw, http.StatusBadGateway, res.Error.Error())
+		return nil, nil, false
 	}
-
-	return keys, nil
+	return config, body, true
 }
 
-//nolint:gocyclo // complex query handling with multiple validation paths and dynamic column types
-func (d *NetXMSDatasource) handleTableQuery(ctx context.Context, req *backend.QueryDataRequest, queryConfig tableQueryConfig) (*backend.QueryDataResponse, error) {
-	response := backend.NewQueryDataResponse()
-
-	for _, q := range req.Queries {
-		var qm map[string]any
-		if err := json.Unmarshal(q.JSON, &qm); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
-			continue
-		}
-
-		valid := true
-		for _, req := range queryConfig.required {
-			if value, ok := qm[req.field].(string); !ok || value == "" {
-				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, req.message)
-				valid = false
-				break
-			}
-		}
-		if !valid {
-			continue
-		}
-
-		pluginConfig, err := models.LoadPluginSettings(*req.PluginContext.DataSourceInstanceSettings)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
-			continue
-		}
-
-		client := &http.Client{
-			Timeout: 10 * time.Second,
-		}
-
-		url := joinURL(pluginConfig.ServerAddress, queryConfig.url)
-
-		reqBody, err := queryConfig.formatBody(qm)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to format request body: %v", err))
-			continue
-		}
-
-		bodyBytes, err := json.Marshal(reqBody)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err))
-			continue
-		}
-
-		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyBytes))
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
-			continue
-		}
-
-		request.Header.Set("Content-Type", "application/json")
-		request.Header.Add("Authorization", "Bearer "+pluginConfig.Secrets.ApiKey)
-
-		result, err := client.Do(request)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
-			continue
-		}
-
-		body, err := io.ReadAll(result.Body)
-		result.Body.Close()
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
-			continue
-		}
-
-		if result.StatusCode == http.StatusUnauthorized {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusUnauthorized, "Unauthorized: Invalid API key")
-			continue
-		}
-
-		if result.StatusCode != http.StatusOK {
-			response.Responses[q.RefID] = parseErrorResponse(result.StatusCode, body)
-			continue
-		}
-
-		var tableResponse []map[string]any
-		if err := json.Unmarshal(body, &tableResponse); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
-			continue
-		}
-
-		frame := data.NewFrame(queryConfig.frameName)
-
-		if len(tableResponse) > 0 { //nolint:nestif // ordered column extraction requires nested decoding
-			dec := json.NewDecoder(bytes.NewReader(body))
-			if token, err := dec.Token(); err != nil || token != json.Delim('[') {
-				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("expected array, got %v", token))
-				continue
-			}
-
-			if !dec.More() {
-				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, "empty array")
-				continue
-			}
-
-			var firstObject json.RawMessage
-			if err := dec.Decode(&firstObject); err != nil {
-				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to decode first object: %v", err))
-				continue
-			}
-
-			columnOrder, err := decodeJSONKeyOrder(firstObject)
-			if err != nil {
-				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse first row: %v", err))
-				continue
-			}
-
-			columnValues := make(map[string][]any)
-			for _, columnName := range columnOrder {
-				columnValues[columnName] = make([]any, len(tableResponse))
-			}
-
-			for i, row := range tableResponse {
-				for _, columnName := range columnOrder {
-					val := row[columnName]
-					if val == nil {
-						columnValues[columnName][i] = nil
-						continue
-					}
-
-					switch v := val.(type) {
-					case string:
-						columnValues[columnName][i] = v
-					case float64:
-						columnValues[columnName][i] = v
-					case int:
-						columnValues[columnName][i] = float64(v)
-					case int64:
-						columnValues[columnName][i] = float64(v)
-					case bool:
-						columnValues[columnName][i] = v
-					case []any:
-						columnValues[columnName][i] = fmt.Sprintf("%v", v)
-					default:
-						columnValues[columnName][i] = fmt.Sprintf("%v", v)
-					}
-				}
-			}
-
-			for _, columnName := range columnOrder {
-				values := columnValues[columnName]
-				var field *data.Field
-				if len(values) > 0 && values[0] != nil {
-					switch values[0].(type) {
-					case float64:
-						field = data.NewField(columnName, nil, values)
-					case bool:
-						field = data.NewField(columnName, nil, values)
-					default:
-						strValues := make([]string, len(values))
-						for i, v := range values {
-							if v == nil {
-								strValues[i] = ""
-							} else {
-								strValues[i] = fmt.Sprintf("%v", v)
-							}
-						}
-						field = data.NewField(columnName, nil, strValues)
-					}
-				} else {
-					field = data.NewField(columnName, nil, make([]string, len(values)))
-				}
-				frame.Fields = append(frame.Fields, field)
-			}
-		}
-
-		response.Responses[q.RefID] = backend.DataResponse{
-			Frames: data.Frames{frame},
-		}
+// handleVersion returns plugin version together with connected server version
+func (ds *NetXMSDatasource) handleVersion(rw http.ResponseWriter, req *http.Request) {
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, fmt.Sprintf("failed to load plugin settings: %v", err))
+		return
 	}
 
-	return response, nil
-}
-
-func (d *NetXMSDatasource) handleSummaryTableQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	return d.handleTableQuery(ctx, req, tableQueryConfig{
-		url:       "/v1/grafana/infinity/summary-table",
-		frameName: "summary-table",
-		required: []requiredField{
-			{"summaryTableId", "tableId is required"},
-		},
-		formatBody: func(qm map[string]any) (map[string]any, error) {
-			reqBody := make(map[string]any)
-
-			if rootObjectId, ok := qm["sourceObjectId"].(string); ok && rootObjectId != "" {
-				rootObjectIdNum, err := strconv.ParseInt(rootObjectId, 10, 64)
-				if err != nil {
-					return nil, fmt.Errorf("invalid rootObjectId: %w", err)
-				}
-				reqBody["rootObjectId"] = rootObjectIdNum
-			}
-
-			if tableId, ok := qm["summaryTableId"].(string); ok && tableId != "" {
-				tableIdNum, err := strconv.ParseInt(tableId, 10, 64)
-				if err != nil {
-					return nil, fmt.Errorf("invalid tableId: %w", err)
-				}
-				reqBody["tableId"] = tableIdNum
-			}
-
-			return reqBody, nil
-		},
-	})
-}
-
-func (d *NetXMSDatasource) handleObjectQueryQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	return d.handleTableQuery(ctx, req, tableQueryConfig{
-		url:       "/v1/grafana/infinity/object-query",
-		frameName: "object-query",
-		required: []requiredField{
-			{"objectQueryId", "queryId is required"},
-		},
-		formatBody: func(qm map[string]any) (map[string]any, error) {
-			reqBody := make(map[string]any)
-
-			if rootObjectId, ok := qm["sourceObjectId"].(string); ok && rootObjectId != "" {
-				rootObjectIdNum, err := strconv.ParseInt(rootObjectId, 10, 64)
-				if err != nil {
-					return nil, fmt.Errorf("invalid rootObjectId: %w", err)
-				}
-				reqBody["rootObjectId"] = rootObjectIdNum
-			}
-
-			if queryId, ok := qm["objectQueryId"].(string); ok && queryId != "" {
-				queryIdNum, err := strconv.ParseInt(queryId, 10, 64)
-				if err != nil {
-					return nil, fmt.Errorf("invalid queryId: %w", err)
-				}
-				reqBody["queryId"] = queryIdNum
-			}
-
-			if values, ok := qm["queryParameters"].(string); ok && values != "" {
-				var parsedValues []map[string]any
-				if err := json.Unmarshal([]byte(values), &parsedValues); err != nil {
-					return nil, fmt.Errorf("invalid queryParameters JSON: %w", err)
-				}
-				reqBody["values"] = parsedValues
-			}
+	serverVersion, err := ds.getServerVersion(req.Context(), config)
+	if err != nil {
+		writeJSONError(rw, http.StatusBadGateway, err.Error())
+		return
+	}
 
-			return reqBody, nil
-		},
+	body, err := json.Marshal(map[string]string{
+		"pluginVersion": pCtx.PluginVersion,
+		"serverVersion": serverVersion,
 	})
-}
-
-type objectStatusResponse struct {
-	Name   string `json:"Name"`
-	Status int32  `json:"Status"`
-}
-
-func (d *NetXMSDatasource) handleObjectStatusQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	response := backend.NewQueryDataResponse()
-
-	for _, q := range req.Queries {
-		var qm queryModel
-		if err := json.Unmarshal(q.JSON, &qm); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
-			continue
-		}
-
-		pluginConfig, err := models.LoadPluginSettings(*req.PluginContext.DataSourceInstanceSettings)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
-			continue
-		}
-
-		client := &http.Client{
-			Timeout: 10 * time.Second,
-		}
-
-		url := joinURL(pluginConfig.ServerAddress, "/v1/grafana/objects-status")
-
-		reqBody := map[string]any{}
-		if qm.SourceObjectId != "" {
-			rootObjectIdNum, parseErr := strconv.ParseInt(qm.SourceObjectId, 10, 64)
-			if parseErr != nil {
-				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, "sourceObjectId must be numeric")
-				continue
-			}
-			reqBody["rootObjectId"] = rootObjectIdNum
-		}
-
-		bodyBytes, err := json.Marshal(reqBody)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err))
-			continue
-		}
-
-		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyBytes))
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
-			continue
-		}
-
-		request.Header.Set("Content-Type", "application/json")
-		request.Header.Add("Authorization", "Bearer "+pluginConfig.Secrets.ApiKey)
-
-		result, err := client.Do(request)
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
-			continue
-		}
-
-		body, err := io.ReadAll(result.Body)
-		result.Body.Close()
-		if err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
-			continue
-		}
-
-		if result.StatusCode == http.StatusUnauthorized {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusUnauthorized, "Unauthorized: Invalid API key")
-			continue
-		}
-
-		if result.StatusCode != http.StatusOK {
-			response.Responses[q.RefID] = parseErrorResponse(result.StatusCode, body)
-			continue
-		}
-
-		var statusData []objectStatusResponse
-		if err := json.Unmarshal(body, &statusData); err != nil {
-			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
-			continue
-		}
-
-		color := []string{
-			"rgb(0, 137, 0)",     // Normal
-			"rgb(0, 142, 145)",   // Warning
-			"rgb(201, 198, 0)",   // Minor
-			"rgb(223, 102, 0)",   // Major
-			"rgb(160, 0, 0)",     // Critical
-			"rgb(33, 33, 248)",   // Unknown
-			"rgb(113, 113, 113)", // Unmanaged
-			"rgb(100, 41, 0)",    // Disabled
-			"rgb(138, 0, 143)",   // Testing
-		}
-
-		frames := make(data.Frames, 0, len(statusData))
-		for _, obj := range statusData {
-			frame := data.NewFrame(obj.Name)
-
-			statusColor := "rgb(128, 128, 128)"
-			if obj.Status >= 0 && int(obj.Status) < len(color) {
-				statusColor = color[obj.Status]
-			}
-
-			// Use DisplayName to show object name in stat panel
-			nameField := data.NewField("Name", nil, []string{obj.Name})
-			nameField.Config = &data.FieldConfig{
-				Mappings: data.ValueMappings{
-					data.ValueMapper{
-						obj.Name: {Text: obj.Name, Color: statusColor},
-					},
-				},
-			}
-			frame.Fields = append(frame.Fields, nameField)
-			frames = append(frames, frame)
-		}
-
-		response.Responses[q.RefID] = backend.DataResponse{
-			Frames: frames,
-		}
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to marshal response")
+		return
+	}
+	writeJSONResponse(rw, body)
+}
+
+// effectiveConfig describes settings as parsed by plugin, with defaults applied.
+// API key is never included, only whether it is configured.
+type effectiveConfig struct {
+	ServerAddress         string             `json:"serverAddress"`
+	WebUIAddress          string             `json:"webUiAddress"`
+	APIVersion            string             `json:"apiVersion"`
+	APIKeyConfigured      bool               `json:"apiKeyConfigured"`
+	SortObjects           bool               `json:"sortObjects"`
+	ShowObjectPaths       bool               `json:"showObjectPaths"`
+	Timeout               int                `json:"timeout"`
+	ConnectTimeout        int                `json:"connectTimeout"`
+	HTTP2                 string             `json:"http2"`
+	RetryOnRateLimit      bool               `json:"retryOnRateLimit"`
+	Timeouts              map[string]float64 `json:"timeouts,omitempty"`
+	DefaultTimeRange      int                `json:"defaultTimeRange"`
+	ObjectQueryCacheTTL   int                `json:"objectQueryCacheTTL"`
+	MaxConcurrentRequests int                `json:"maxConcurrentRequests"`
+	MaxQueriesPerRequest  int                `json:"maxQueriesPerRequest"`
+	SlowResponseThreshold int                `json:"slowResponseThresholdMs"`
+	FailureMode           string             `json:"failureMode"`
+	LenientJSON           bool               `json:"lenientJson"`
+	StatusColors          map[string]string  `json:"statusColors,omitempty"`
+	NullValues            []string           `json:"nullValues"`
+	ServerTimezone        string             `json:"serverTimezone"`
+	AllowAlarmAcknowledge bool               `json:"allowAlarmAcknowledge"`
+}
+
+// handleConfig returns effective non-secret settings, so users can verify what plugin actually parsed
+func (ds *NetXMSDatasource) handleConfig(rw http.ResponseWriter, req *http.Request) {
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, fmt.Sprintf("failed to load plugin settings: %v", err))
+		return
 	}
 
-	return response, nil
-}
-
-// parseErrorResponse extracts error message from response body and returns appropriate DataResponse
-func parseErrorResponse(statusCode int, body []byte) backend.DataResponse {
-	var reasonResp map[string]string
-	if err := json.Unmarshal(body, &reasonResp); err == nil && reasonResp["reason"] != "" {
-		return backend.ErrDataResponse(httpStatusToBackendStatus(statusCode), "Request error: "+reasonResp["reason"])
+	body, err := json.Marshal(effectiveConfig{
+		ServerAddress:         config.ServerAddress,
+		WebUIAddress:          config.WebUIAddress,
+		APIVersion:            config.GetAPIVersion(),
+		APIKeyConfigured:      config.Secrets.ApiKey != "",
+		SortObjects:           config.SortObjects,
+		ShowObjectPaths:       config.ObjectPaths,
+		Timeout:               int(config.RequestTimeout().Seconds()),
+		ConnectTimeout:        int(config.ConnectTimeout().Seconds()),
+		HTTP2:                 config.HTTP2,
+		RetryOnRateLimit:      config.RetryOn429,
+		Timeouts:              config.Timeouts,
+		DefaultTimeRange:      int(config.FallbackTimeRange().Seconds()),
+		ObjectQueryCacheTTL:   int(config.ObjectQueryCacheTTL().Seconds()),
+		MaxConcurrentRequests: config.MaxConcurrentRequests(),
+		MaxQueriesPerRequest:  config.MaxQueriesPerRequest(),
+		SlowResponseThreshold: int(config.SlowResponseWarning().Milliseconds()),
+		FailureMode:           config.FailureMode,
+		LenientJSON:           config.LenientJSON,
+		StatusColors:          config.StatusColors,
+		NullValues:            config.DCINullValues(),
+		ServerTimezone:        config.TimeZone,
+		AllowAlarmAcknowledge: config.AllowAck,
+	})
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to marshal response")
+		return
 	}
-	return backend.ErrDataResponse(httpStatusToBackendStatus(statusCode), "Request error")
+	writeJSONResponse(rw, body)
 }
 
-// httpStatusToBackendStatus maps HTTP status codes to backend.Status
-func httpStatusToBackendStatus(code int) backend.Status {
-	if code == 400 {
-		return backend.StatusBadRequest
-	}
-	if code == 401 {
-		return backend.StatusUnauthorized
-	}
-	if code == 403 {
-		return backend.StatusForbidden
+// resolveTimeRange returns query time range, falling back to configured window
+// ending now when panel provides zero time range
+func resolveTimeRange(timeRange backend.TimeRange, config *models.PluginSettings) (time.Time, time.Time) {
+	if !timeRange.From.IsZero() && !timeRange.To.IsZero() {
+		return timeRange.From, timeRange.To
 	}
-	if code == 404 {
-		return backend.StatusNotFound
-	}
-	if code >= 500 && code < 600 {
-		return backend.StatusInternal
-	}
-	return backend.StatusUnknown
+	to := time.Now()
+	return to.Add(-config.FallbackTimeRange()), to
 }
 
-func writeJSONResponse(rw http.ResponseWriter, body []byte) {
-	rw.Header().Add("Content-Type", "application/json")
-	rw.WriteHeader(http.StatusOK)
-	_, _ = rw.Write(body)
-}
+// serverTimeLayout is used for history time bounds
+const serverTimeLayout = "2006-01-02T15:04:05"
 
-func joinURL(base, path string) string {
-	base = strings.TrimRight(base, "/")
-	path = strings.TrimLeft(path, "/")
-	return base + "/" + path
+// formatTimeBounds formats history time bounds as local time in configured
+// server time zone, UTC if it is not configured
+func formatTimeBounds(from, to time.Time, config *models.PluginSettings) (string, string, error) {
+	location, err := config.ServerLocation()
+	if err != nil {
+		return "", "", err
+	}
+	return url.QueryEscape(from.In(location).Format(serverTimeLayout)), url.QueryEscape(to.In(location).Format(serverTimeLayout)), nil
 }
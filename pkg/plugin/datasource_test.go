@@ -3,43 +3,20 @@ package plugin
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
 )
 
-// mockAlarmResponse creates a test server that returns mock alarm data
-func mockAlarmResponse() *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify request headers
-		if r.Header.Get("Authorization") != "Bearer test-key" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		// Mock alarm response
-		alarms := []alarmResponse{
-			{
-				Id:         1,
-				Severity:   "Critical",
-				State:      "Active",
-				Source:     "Test Source",
-				Message:    "Test Alarm",
-				Count:      1,
-				AckBy:      "Test User",
-				Created:    time.Now(),
-				LastChange: time.Now(),
-			},
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(alarms)
-	}))
-}
-
 func TestQueryData(t *testing.T) {
 	// Create mock server
 	mockServer := mockAlarmResponse()
@@ -55,15 +32,7 @@ func TestQueryData(t *testing.T) {
 		},
 	}
 
-	instance, err := NewDatasource(context.Background(), settings)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	ds, ok := instance.(*NetXMSDatasource)
-	if !ok {
-		t.Fatal("Failed to type assert instance to *Datasource")
-	}
+	ds := newTestDatasource(t, settings)
 
 	// Create a query model
 	qm := queryModel{
@@ -115,7 +84,662 @@ func TestQueryData(t *testing.T) {
 	}
 
 	// Verify the frame contains our mock data
-	if len(frame.Fields) != 9 {
-		t.Errorf("Expected 9 fields, got: %d", len(frame.Fields))
+	if len(frame.Fields) != 12 {
+		t.Errorf("Expected 12 fields, got: %d", len(frame.Fields))
+	}
+}
+
+func TestCheckHealthInvalidServerAddress(t *testing.T) {
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "ftp://netxms.local"}`),
+		DecryptedSecureJSONData: map[string]string{
+			"apiKey": "test-key",
+		},
+	}
+
+	ds := &NetXMSDatasource{}
+	res, err := ds.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Status != backend.HealthStatusError {
+		t.Errorf("Expected error status, got: %v", res.Status)
+	}
+	if !strings.Contains(res.Message, "unsupported scheme") {
+		t.Errorf("Expected descriptive message, got: %s", res.Message)
+	}
+}
+
+func TestEmptyResponseAsEmptyFrame(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"no content", http.StatusNoContent},
+		{"empty body", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			settings := newTestSettings(server.URL)
+
+			response := runTestQuery(t, settings, "alarms", `{"allowEmptyResponse": true}`)
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got: %v", response.Error)
+			}
+			if len(response.Frames) != 1 || len(response.Frames[0].Fields) != 12 {
+				t.Fatal("Expected empty alarm frame with 12 fields")
+			}
+			if response.Frames[0].Rows() != 0 {
+				t.Errorf("Expected 0 rows, got: %d", response.Frames[0].Rows())
+			}
+
+			response = runTestQuery(t, settings, "dciValues", `{"sourceObjectId": "1", "dciId": "2", "allowEmptyResponse": true}`)
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got: %v", response.Error)
+			}
+			if len(response.Frames) != 1 || len(response.Frames[0].Fields) != 2 {
+				t.Fatal("Expected empty DCI frame with time and value fields")
+			}
+
+			response = runTestQuery(t, settings, "summaryTables", `{"summaryTableId": "1", "allowEmptyResponse": true}`)
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got: %v", response.Error)
+			}
+			if len(response.Frames) != 1 || response.Frames[0].Name != "summary-table" {
+				t.Fatal("Expected empty summary table frame")
+			}
+
+			response = runTestQuery(t, settings, "alarms", `{}`)
+			if response.Error == nil {
+				t.Error("Expected error when empty response is not allowed")
+			}
+		})
+	}
+}
+
+func TestVersionResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"5.2.4"}`))
+	}))
+	defer server.Close()
+
+	settings := newTestSettings(server.URL)
+	ds := newTestDatasource(t, settings)
+
+	var response *backend.CallResourceResponse
+	err := ds.CallResource(context.Background(), &backend.CallResourceRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+			PluginVersion:              "2.0.0",
+		},
+		Method: http.MethodGet,
+		Path:   "version",
+		URL:    "version",
+	}, backend.CallResourceResponseSenderFunc(func(res *backend.CallResourceResponse) error {
+		response = res
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result map[string]string
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result["pluginVersion"] != "2.0.0" {
+		t.Errorf("Expected plugin version 2.0.0, got: %q", result["pluginVersion"])
+	}
+	if result["serverVersion"] != "5.2.4" {
+		t.Errorf("Expected server version 5.2.4, got: %q", result["serverVersion"])
+	}
+}
+
+func TestRawJSONResponse(t *testing.T) {
+	rawBody := `[{"Name":"node1","Value":{"nested":[1,2]}}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(rawBody))
+	}))
+	defer server.Close()
+
+	for _, queryType := range []string{"objectQueries", "alarms"} {
+		response := runTestQuery(t, newTestSettings(server.URL), queryType, `{"objectQueryId": "1", "rawJson": true}`)
+		if response.Error != nil {
+			t.Fatalf("Expected no error, got: %v", response.Error)
+		}
+		frame := response.Frames[0]
+		if len(frame.Fields) != 1 || frame.Rows() != 1 {
+			t.Fatalf("Expected single cell frame for %s", queryType)
+		}
+		if value, _ := frame.Fields[0].At(0).(string); value != rawBody {
+			t.Errorf("Expected raw body %q, got: %q", rawBody, value)
+		}
+	}
+}
+
+func TestQueryCancelledByContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	settings := newTestSettings(server.URL)
+	ds := newTestDatasource(t, settings)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	resp, err := ds.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+		},
+		Queries: []backend.DataQuery{
+			{RefID: "A", QueryType: "alarms", JSON: []byte(`{}`)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Responses["A"].Error == nil {
+		t.Error("Expected error for cancelled request")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected request to be cancelled early, took: %v", elapsed)
+	}
+}
+
+func TestCheckHealthUnresolvableHost(t *testing.T) {
+	settings := newTestSettings("http://netxms-host.invalid")
+	dials := 0
+	notFound := &net.DNSError{Err: "no such host", Name: "netxms-host.invalid", IsNotFound: true}
+	ds := &NetXMSDatasource{transport: dnsFailureTransport("", &dials, notFound, notFound)}
+	res, err := ds.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Status != backend.HealthStatusError {
+		t.Errorf("Expected error status, got: %v", res.Status)
+	}
+	if res.Message != "hostname could not be resolved: netxms-host.invalid" {
+		t.Errorf("Unexpected message: %s", res.Message)
+	}
+	if dials != 1 {
+		t.Errorf("Expected unknown host not to be retried, got %d attempts", dials)
+	}
+}
+
+func TestCheckHealthTemporaryResolverFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"5.2.4"}`))
+	}))
+	defer server.Close()
+
+	settings := newTestSettings("http://netxms-host.invalid")
+	dials := 0
+	temporary := &net.DNSError{Err: "server misbehaving", Name: "netxms-host.invalid", IsTemporary: true}
+	ds := &NetXMSDatasource{transport: dnsFailureTransport(server.Listener.Addr().String(), &dials, temporary)}
+	res, err := ds.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Status != backend.HealthStatusOk {
+		t.Errorf("Expected temporary failure to be retried, got: %s", res.Message)
+	}
+	if dials != 2 {
+		t.Errorf("Expected 2 attempts, got %d", dials)
+	}
+}
+
+func TestSlowResponseNotice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "slowResponseThresholdMs": 10}`),
+	}
+	response := runTestQuery(t, settings, "alarms", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	meta := response.Frames[0].Meta
+	if meta == nil || len(meta.Notices) != 1 || !strings.HasPrefix(meta.Notices[0].Text, "Slow response") {
+		t.Fatalf("Expected slow response notice, got: %+v", meta)
+	}
+	if meta.Notices[0].Severity != data.NoticeSeverityInfo {
+		t.Errorf("Expected informational notice, got: %v", meta.Notices[0].Severity)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "alarms", `{"sourceObjectId": "1"}`)
+	if meta := response.Frames[0].Meta; meta != nil && len(meta.Notices) > 0 {
+		t.Errorf("Expected no notice below default threshold, got: %+v", meta.Notices)
+	}
+}
+
+func TestQueryDataKeepsBatches(t *testing.T) {
+	var calls, queries int
+	ds := &NetXMSDatasource{
+		queryHandler: backend.QueryDataHandlerFunc(func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			calls++
+			queries += len(req.Queries)
+			return backend.NewQueryDataResponse(), nil
+		}),
+	}
+	_, err := ds.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{RefID: "A"}, {RefID: "B"}, {RefID: "C"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || queries != 3 {
+		t.Errorf("Expected all queries passed to handler at once, got: %d calls with %d queries", calls, queries)
+	}
+}
+
+func TestNumericQueryIDs(t *testing.T) {
+	var paths []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "history") {
+			_, _ = w.Write([]byte(`{"description":"CPU","values":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"Name":"node1"}]`))
+	}))
+	defer server.Close()
+
+	for _, query := range []string{`{"sourceObjectId": "12", "dciId": "34"}`, `{"sourceObjectId": 12, "dciId": 34}`} {
+		paths = nil
+		response := runTestQuery(t, newTestSettings(server.URL), "dciValues", query)
+		if response.Error != nil {
+			t.Fatalf("Expected no error for %s, got: %v", query, response.Error)
+		}
+		if len(paths) != 1 || paths[0] != "/v1/objects/12/data-collection/34/history" {
+			t.Errorf("Unexpected upstream path for %s: %v", query, paths)
+		}
+	}
+
+	for _, query := range []string{`{"sourceObjectId": "12", "objectQueryId": "5"}`, `{"sourceObjectId": 12, "objectQueryId": 5}`} {
+		bodies = nil
+		response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", query)
+		if response.Error != nil {
+			t.Fatalf("Expected no error for %s, got: %v", query, response.Error)
+		}
+		if len(bodies) != 1 || bodies[0]["rootObjectId"] != float64(12) {
+			t.Errorf("Expected rootObjectId 12 to be forwarded for %s, got: %v", query, bodies)
+		}
+	}
+}
+
+func TestQueryTypeTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "history") {
+			_, _ = w.Write([]byte(`{"description":"CPU","values":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "timeout": 30, "timeouts": {"dciValues": 0.05}}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+
+	start := time.Now()
+	response := runTestQuery(t, settings, "dciValues", `{"sourceObjectId": "1", "dciId": "2"}`)
+	if response.Error == nil {
+		t.Fatal("Expected DCI query to time out with per-type timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected 50ms DCI timeout to be used, took: %v", elapsed)
+	}
+
+	response = runTestQuery(t, settings, "alarms", `{}`)
+	if response.Error != nil {
+		t.Errorf("Expected alarm query to use global timeout, got: %v", response.Error)
+	}
+}
+
+func TestConfigResource(t *testing.T) {
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "http://netxms.local", "timeouts": {"dciValues": 60}}`),
+		DecryptedSecureJSONData: map[string]string{
+			"apiKey": "secret-api-key",
+		},
+	}
+
+	response := callTestResource(t, settings, "config")
+	if response.Status != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", response.Status)
+	}
+	if strings.Contains(string(response.Body), "secret-api-key") {
+		t.Fatal("API key must not be present in config response")
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal(response.Body, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config["serverAddress"] != "http://netxms.local" {
+		t.Errorf("Unexpected server address: %v", config["serverAddress"])
+	}
+	if config["apiKeyConfigured"] != true {
+		t.Error("Expected API key to be reported as configured")
+	}
+	if timeout, _ := config["timeout"].(float64); timeout != 10 {
+		t.Errorf("Expected default timeout 10, got: %v", config["timeout"])
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		valid    bool
+	}{
+		{"12", 12, true},
+		{"1.23e9", 1230000000, true},
+		{"1E3", 1000, true},
+		{"4.0", 4, true},
+		{"1.5", 0, false},
+		{"1e30", 0, false},
+		{"node", 0, false},
+	}
+	for _, tt := range tests {
+		id, err := parseID(tt.input)
+		if tt.valid && (err != nil || id != tt.expected) {
+			t.Errorf("Expected %s to parse as %d, got: %d (%v)", tt.input, tt.expected, id, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Expected error for %s, got: %d", tt.input, id)
+		}
+	}
+}
+
+func TestScientificNotationQueryID(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[]}`))
+	}))
+	defer server.Close()
+
+	for _, query := range []string{`{"sourceObjectId": 1.23e9, "dciId": 34}`, `{"sourceObjectId": "1.23e9", "dciId": "34"}`} {
+		response := runTestQuery(t, newTestSettings(server.URL), "dciValues", query)
+		if response.Error != nil {
+			t.Fatalf("Expected no error for %s, got: %v", query, response.Error)
+		}
+		if path != "/v1/objects/1230000000/data-collection/34/history" {
+			t.Errorf("Unexpected upstream path for %s: %s", query, path)
+		}
+	}
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": 12.5, "dciId": 34}`)
+	if response.Error == nil {
+		t.Error("Expected error for fractional id")
+	}
+}
+
+func TestHealthyStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	check := func(jsonData string) *backend.CheckHealthResult {
+		settings := backend.DataSourceInstanceSettings{
+			JSONData:                []byte(jsonData),
+			DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+		}
+		ds := &NetXMSDatasource{}
+		res, err := ds.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &settings},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := check(`{"serverAddress": "` + server.URL + `"}`); res.Status != backend.HealthStatusError {
+		t.Errorf("Expected 204 to be unhealthy by default, got: %v (%s)", res.Status, res.Message)
+	}
+	if res := check(`{"serverAddress": "` + server.URL + `", "healthyStatuses": [200, 204]}`); res.Status != backend.HealthStatusOk {
+		t.Errorf("Expected 204 to be healthy when configured, got: %v (%s)", res.Status, res.Message)
+	}
+
+	config := &models.PluginSettings{Healthy: []int{200, 204}}
+	if _, failed := handleUpstreamError(http.StatusNoContent, nil, config); failed {
+		t.Error("Expected configured healthy status to be accepted by query handlers")
+	}
+
+	// Query handlers return empty results for configured healthy status without body
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "healthyStatuses": [200, 204]}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+	queries := map[string]string{
+		"alarms":         `{}`,
+		"alarmCount":     `{}`,
+		"dciValues":      `{"sourceObjectId": "1", "dciId": "2"}`,
+		"objectQueries":  `{"objectQueryId": "1"}`,
+		"objectStatus":   `{"sourceObjectId": "1"}`,
+		"objectChildren": `{"sourceObjectId": "1"}`,
+		"serverInfo":     `{}`,
+	}
+	for queryType, query := range queries {
+		response := runTestQuery(t, settings, queryType, query)
+		if response.Error != nil {
+			t.Errorf("%s: expected empty result for healthy 204, got: %v", queryType, response.Error)
+		}
+	}
+	if response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`); response.Error == nil {
+		t.Error("Expected 204 to fail queries when not configured as healthy")
+	}
+}
+
+func TestResourceCancellation(t *testing.T) {
+	started := make(chan struct{})
+	aborted := make(chan bool, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+			aborted <- true
+		case <-time.After(5 * time.Second):
+			aborted <- false
+		}
+	}))
+	defer server.Close()
+
+	settings := newTestSettings(server.URL)
+	ds := newTestDatasource(t, settings)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = ds.CallResource(ctx, &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &settings},
+			Method:        http.MethodGet,
+			Path:          "dcis",
+			URL:           "dcis?objectId=1",
+		}, backend.CallResourceResponseSenderFunc(func(*backend.CallResourceResponse) error { return nil }))
+	}()
+
+	<-started
+	cancel()
+	if !<-aborted {
+		t.Error("Expected upstream request to be aborted when resource call is cancelled")
+	}
+	<-done
+}
+
+func TestServerInfoQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/server-info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"5.2.4","uptime":86400,"license":{"valid":true,"expires":null},"addresses":["10.0.0.1","10.0.0.2"]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "serverInfo", `{}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	pairs := map[string]string{}
+	for i := range frame.Rows() {
+		pairs[frame.Fields[0].At(i).(string)] = frame.Fields[1].At(i).(string)
+	}
+	expected := map[string]string{
+		"addresses.0":     "10.0.0.1",
+		"addresses.1":     "10.0.0.2",
+		"license.expires": "",
+		"license.valid":   "true",
+		"uptime":          "86400",
+		"version":         "5.2.4",
+	}
+	if fmt.Sprint(pairs) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got: %v", expected, pairs)
+	}
+	if key, _ := frame.Fields[0].At(0).(string); key != "addresses.0" {
+		t.Errorf("Expected keys in sorted order, got first key: %s", key)
+	}
+}
+
+func TestQueryWithEmptyServerAddress(t *testing.T) {
+	settings := newTestSettings("")
+	for _, queryType := range []string{"alarms", "dciValues", "objectStatus"} {
+		t.Run(queryType, func(t *testing.T) {
+			response := runTestQuery(t, settings, queryType, `{"sourceObjectId": "1", "dciId": "2"}`)
+			if response.Error == nil || !strings.Contains(response.Error.Error(), "server address is missing") {
+				t.Errorf("Expected missing server address error, got: %v", response.Error)
+			}
+		})
+	}
+}
+
+func TestFailureModeEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"reason":"Database unavailable"}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`)
+	if response.Error == nil {
+		t.Fatal("Expected error by default")
+	}
+
+	settings := newTestSettings(server.URL)
+	settings.JSONData = []byte(`{"serverAddress": "` + server.URL + `", "failureMode": "empty"}`)
+	for _, queryType := range []string{"alarms", "objectStatus"} {
+		response = runTestQuery(t, settings, queryType, `{"sourceObjectId": "1"}`)
+		if response.Error != nil {
+			t.Fatalf("Expected no error for %s in empty failure mode, got: %v", queryType, response.Error)
+		}
+		if len(response.Frames) != 1 || len(response.Frames[0].Fields) != 0 {
+			t.Fatalf("Expected single empty frame for %s, got: %v", queryType, response.Frames)
+		}
+		meta := response.Frames[0].Meta
+		if meta == nil || len(meta.Notices) != 1 || meta.Notices[0].Text != "Request error: Database unavailable" {
+			t.Errorf("Expected error kept as notice for %s, got: %+v", queryType, meta)
+		}
+	}
+}
+
+func TestMaxQueriesPerRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "maxQueriesPerRequest": 2}`),
+	}
+	queries := make([]backend.DataQuery, 3)
+	for i := range queries {
+		queries[i] = backend.DataQuery{RefID: string(rune('A' + i)), QueryType: "alarms", JSON: []byte(`{}`)}
+	}
+	resp, err := newTestDatasource(t, settings).QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{DataSourceInstanceSettings: &settings},
+		Queries:       queries,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, refID := range []string{"A", "B"} {
+		if resp.Responses[refID].Error != nil {
+			t.Errorf("Expected query %s within limit to succeed, got: %v", refID, resp.Responses[refID].Error)
+		}
+	}
+	if res := resp.Responses["C"]; res.Error == nil || res.Error.Error() != "too many queries in request, at most 2 are processed" {
+		t.Errorf("Expected limit error for excess query, got: %v", res.Error)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected excess query not to reach server, got %d requests", requests.Load())
+	}
+
+	// Excess queries follow configured failure mode like other failed queries
+	settings.JSONData = []byte(`{"serverAddress": "` + server.URL + `", "maxQueriesPerRequest": 2, "failureMode": "empty"}`)
+	resp, err = newTestDatasource(t, settings).QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{DataSourceInstanceSettings: &settings},
+		Queries:       queries,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := resp.Responses["C"]
+	if res.Error != nil || len(res.Frames) != 1 || res.Frames[0].Meta == nil || !strings.HasPrefix(res.Frames[0].Meta.Notices[0].Text, "too many queries") {
+		t.Errorf("Expected empty frame with limit notice in empty failure mode, got: %v %+v", res.Error, res.Frames)
 	}
 }
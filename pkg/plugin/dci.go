@@ -0,0 +1,878 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+// maxObjectDcis limits number of DCIs requested by single "all DCIs" query
+const maxObjectDcis = 50
+
+// defaultHistogramBuckets is number of buckets of DCI histogram when not set in query
+const defaultHistogramBuckets = 10
+
+// maxHistogramBuckets limits number of buckets of DCI histogram
+const maxHistogramBuckets = 1000
+
+// maxConcurrentDciRequests limits number of parallel history requests for "all DCIs" query
+const maxConcurrentDciRequests = 4
+
+// serverAggregationFunctions lists aggregation functions supported by DCI history endpoint
+var serverAggregationFunctions = map[string]bool{
+	"avg": true,
+	"min": true,
+	"max": true,
+	"sum": true,
+}
+
+// DCI value derivative modes
+const (
+	derivativeNone  = "none"
+	derivativeDelta = "delta" // difference between consecutive values
+	derivativeRate  = "rate"  // difference per second
+)
+
+// DCI series name sources
+const (
+	seriesNameDescription = "description"
+	seriesNameObject      = "objectName"
+	seriesNameCustom      = "custom" // name given in query
+	seriesNameLabel       = "label"  // value of value field label
+)
+
+type dciValueResponse struct {
+	Description string     `json:"description"`
+	UnitName    string     `json:"unitName"`
+	Values      []dciValue `json:"values"`
+}
+
+type dciValue struct {
+	Timestamp string `json:"timestamp"`
+	Value     string `json:"value"`
+	Status    string `json:"status,omitempty"` // threshold state of the point, provided by some server versions
+	// Bucket aggregates, provided instead of value when server aggregates history
+	Min *float64 `json:"min,omitempty"`
+	Avg *float64 `json:"avg,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// isAggregated checks if point holds min/avg/max bucket aggregates instead of single value
+func (v dciValue) isAggregated() bool {
+	return v.Min != nil || v.Avg != nil || v.Max != nil
+}
+
+func (ds *NetXMSDatasource) handleDciList(rw http.ResponseWriter, req *http.Request) {
+	objectID, ok := objectIDParam(rw, req)
+	if !ok {
+		return
+	}
+	path := fmt.Sprintf("/v1/grafana/objects/%s/dci-list", objectID)
+	ds.handleQuery(path, rw, req)
+}
+
+// dciDataTypes lists names of DCI data types by their numeric code
+var dciDataTypes = []string{"int32", "uint32", "int64", "uint64", "string", "float", "null", "counter32", "counter64"}
+
+// dciTypeInfo describes DCI data type and kind of values it produces:
+// "numeric", "string", "table" or "unknown"
+type dciTypeInfo struct {
+	DataType string `json:"dataType"`
+	Kind     string `json:"kind"`
+}
+
+// handleDciType returns data type of given DCI, so query editor can suggest
+// suitable visualization before running the query
+func (ds *NetXMSDatasource) handleDciType(rw http.ResponseWriter, req *http.Request) {
+	objectID, ok := objectIDParam(rw, req)
+	if !ok {
+		return
+	}
+	dciID, ok := idParam(rw, req, "dciId")
+	if !ok {
+		return
+	}
+
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, fmt.Sprintf("failed to load plugin settings: %v", err))
+		return
+	}
+
+	dciURL := joinURL(config.ServerAddress, fmt.Sprintf("/v1/objects/%s/data-collection/%s", objectID, dciID))
+	request, err := newAuthenticatedRequest(req.Context(), http.MethodGet, dciURL, nil, config)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to create request")
+		return
+	}
+	result, err := ds.httpClient(req.Context(), config).Do(request)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to connect to server")
+		return
+	}
+	defer result.Body.Close()
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to read response")
+		return
+	}
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		writeJSONError(rw, http.StatusBadGateway, res.Error.Error())
+		return
+	}
+
+	var dci struct {
+		DciType  string `json:"dciType"`
+		DataType any    `json:"dataType"`
+	}
+	if err := json.Unmarshal(body, &dci); err != nil {
+		writeJSONError(rw, http.StatusBadGateway, fmt.Sprintf("failed to parse DCI: %v", err))
+		return
+	}
+	response, err := json.Marshal(resolveDciType(dci.DciType, dci.DataType))
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to marshal response")
+		return
+	}
+	writeJSONResponse(rw, response)
+}
+
+// resolveDciType converts DCI type and data type reported by server, given
+// either as name or as numeric code, into data type name and value kind
+func resolveDciType(dciType string, dataType any) dciTypeInfo {
+	if strings.EqualFold(dciType, "table") {
+		return dciTypeInfo{DataType: "table", Kind: "table"}
+	}
+
+	var name string
+	switch value := dataType.(type) {
+	case string:
+		name = strings.ToLower(strings.TrimSpace(value))
+		if code, err := strconv.Atoi(name); err == nil && code >= 0 && code < len(dciDataTypes) {
+			name = dciDataTypes[code]
+		}
+	case float64:
+		if code := int(value); float64(code) == value && code >= 0 && code < len(dciDataTypes) {
+			name = dciDataTypes[code]
+		}
+	}
+
+	switch name {
+	case "":
+		return dciTypeInfo{DataType: "unknown", Kind: "unknown"}
+	case "string":
+		return dciTypeInfo{DataType: name, Kind: "string"}
+	case "null":
+		return dciTypeInfo{DataType: name, Kind: "unknown"}
+	}
+	if slices.Contains(dciDataTypes, name) {
+		return dciTypeInfo{DataType: name, Kind: "numeric"}
+	}
+	return dciTypeInfo{DataType: name, Kind: "unknown"}
+}
+
+func (ds *NetXMSDatasource) handleDciValues(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		var qm queryModel
+		if err := json.Unmarshal(q.JSON, &qm); err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+
+		if message := validateDciQuery(qm); message != "" {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, message)
+			continue
+		}
+
+		config, err := models.LoadPluginSettings(*req.PluginContext.DataSourceInstanceSettings)
+		if err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+			continue
+		}
+		config = config.ForQueryType("dciValues")
+
+		response.Responses[q.RefID] = ds.timeQuery(func() backend.DataResponse {
+			var res backend.DataResponse
+			if qm.AllDcis {
+				res = ds.allDciQuery(ctx, config, q.TimeRange, qm)
+			} else {
+				res = ds.dciQuery(ctx, config, q.TimeRange, qm)
+			}
+			if qm.SeriesNameFrom != "" && res.Error == nil {
+				var objectName string
+				if qm.SeriesNameFrom == seriesNameObject {
+					objectName = ds.fetchObjectName(ctx, config, string(qm.SourceObjectId))
+				}
+				applySeriesName(res.Frames, qm, objectName)
+			}
+			return res
+		})
+	}
+	return response, nil
+}
+
+// applySeriesName names DCI frames and their value fields after source
+// selected in query, so that series are told apart in panel legends and after
+// merge transformation. Frame name is kept if selected source is not available.
+func applySeriesName(frames data.Frames, qm queryModel, objectName string) {
+	for _, frame := range frames {
+		name := frame.Name
+		switch qm.SeriesNameFrom {
+		case seriesNameObject:
+			if objectName != "" {
+				name = objectName
+			}
+		case seriesNameCustom:
+			name = strings.TrimSpace(qm.SeriesName)
+		case seriesNameLabel:
+			for _, field := range frame.Fields {
+				if value := field.Labels[qm.SeriesNameLabel]; value != "" {
+					name = value
+					break
+				}
+			}
+		}
+		if name == "" {
+			continue
+		}
+
+		frame.Name = name
+		if field, _ := frame.FieldByName("value"); field != nil {
+			field.Name = name
+			if field.Config == nil {
+				field.Config = &data.FieldConfig{}
+			}
+			field.Config.DisplayNameFromDS = name
+		}
+	}
+}
+
+// fetchObjectName requests name of object, empty string if it is not available
+func (ds *NetXMSDatasource) fetchObjectName(ctx context.Context, config *models.PluginSettings, objectId string) string {
+	objectURL := joinURL(config.ServerAddress, fmt.Sprintf("v1/objects/%s", objectId))
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, objectURL, nil, config)
+	if err != nil {
+		return ""
+	}
+	result, err := ds.httpClient(ctx, config).Do(request)
+	if err != nil {
+		return ""
+	}
+	defer result.Body.Close()
+	body, err := readResponseBody(result.Body, config)
+	if err != nil || !config.IsHealthyStatus(result.StatusCode) {
+		return ""
+	}
+	var object struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return ""
+	}
+	return object.Name
+}
+
+// validateDciQuery checks DCI query fields before sending request upstream,
+// returning error message or empty string if query is valid
+func validateDciQuery(qm queryModel) string {
+	if qm.SourceObjectId == "" {
+		return "sourceObjectId is required"
+	}
+	if _, err := strconv.ParseInt(string(qm.SourceObjectId), 10, 64); err != nil {
+		return "sourceObjectId must be numeric"
+	}
+	if !qm.AllDcis {
+		if qm.DciId == "" {
+			return "dciId is required"
+		}
+		if _, err := strconv.ParseInt(string(qm.DciId), 10, 64); err != nil {
+			return "dciId must be numeric"
+		}
+	}
+	if qm.ServerAggregation != "" && !serverAggregationFunctions[qm.ServerAggregation] {
+		return fmt.Sprintf("unsupported serverAggregation: %s", qm.ServerAggregation)
+	}
+	switch qm.Derivative {
+	case "", derivativeNone, derivativeDelta, derivativeRate:
+	default:
+		return fmt.Sprintf("unsupported derivative: %s", qm.Derivative)
+	}
+	if qm.Percentile != nil {
+		if *qm.Percentile < 0 || *qm.Percentile > 100 {
+			return "percentile must be between 0 and 100"
+		}
+		if qm.Histogram {
+			return "percentile can't be combined with histogram"
+		}
+	}
+	if qm.Histogram && qm.AllDcis {
+		return "histogram can't be combined with allDcis"
+	}
+	if qm.MaxPoints < 0 {
+		return "maxPoints must not be negative"
+	}
+	switch qm.SeriesNameFrom {
+	case "", seriesNameDescription:
+	case seriesNameObject:
+		if qm.AllDcis {
+			return "seriesNameFrom objectName can't be combined with allDcis, series would have the same name"
+		}
+	case seriesNameCustom:
+		if qm.AllDcis {
+			return "seriesNameFrom custom can't be combined with allDcis, series would have the same name"
+		}
+		if strings.TrimSpace(qm.SeriesName) == "" {
+			return "seriesName is required for custom series name"
+		}
+	case seriesNameLabel:
+		if qm.SeriesNameLabel == "" {
+			return "seriesNameLabel is required for label series name"
+		}
+	default:
+		return fmt.Sprintf("unsupported seriesNameFrom: %s", qm.SeriesNameFrom)
+	}
+	if qm.Buckets < 0 || qm.Buckets > maxHistogramBuckets {
+		return fmt.Sprintf("buckets must be between 1 and %d", maxHistogramBuckets)
+	}
+	return ""
+}
+
+// dciQuery requests history of single DCI and converts it into frame
+func (ds *NetXMSDatasource) dciQuery(ctx context.Context, config *models.PluginSettings, timeRange backend.TimeRange, qm queryModel) backend.DataResponse {
+	client := ds.httpClient(ctx, config)
+
+	from, to := resolveTimeRange(timeRange, config)
+	timeFrom, timeTo, err := formatTimeBounds(from, to, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+
+	historyURL := joinURL(config.ServerAddress, fmt.Sprintf("v1/objects/%s/data-collection/%s/history?timeFrom=%s&timeTo=%s",
+		qm.SourceObjectId, qm.DciId, timeFrom, timeTo))
+	if qm.ServerAggregation != "" {
+		historyURL += "&function=" + qm.ServerAggregation
+	}
+	if qm.Instance != "" {
+		historyURL += "&instance=" + url.QueryEscape(qm.Instance)
+	}
+	if qm.LastValue {
+		historyURL += "&" + latestValueParameter
+	}
+	if qm.MaxPoints > 0 && !transformsDciValues(qm) {
+		// Transformations need full history, so their result is limited instead
+		historyURL += "&maxPoints=" + strconv.Itoa(qm.MaxPoints)
+	}
+
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, historyURL, nil, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
+	}
+
+	result, err := client.Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
+	}
+
+	body, err := readResponseBody(result.Body, config)
+	result.Body.Close()
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
+	}
+
+	if isEmptyResponse(qm.AllowEmptyResponse, result.StatusCode, body, config) {
+		frame := data.NewFrame("")
+		frame.Fields = append(frame.Fields,
+			data.NewField("time", nil, []time.Time{}),
+			data.NewField("value", nil, []float64{}),
+		)
+		return backend.DataResponse{
+			Frames: data.Frames{frame},
+		}
+	}
+
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
+	}
+
+	if qm.RawJSON {
+		return backend.DataResponse{
+			Frames: data.Frames{rawJSONFrame("dci-values", body)},
+		}
+	}
+
+	var dciData dciValueResponse
+	if err := json.Unmarshal(body, &dciData); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
+	}
+
+	if qm.LastValue {
+		dciData.Values = latestDciValue(dciData.Values)
+	}
+
+	frame, err := buildDciFrame(dciData, config.DCINullValues())
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+
+	if qm.Derivative == derivativeDelta || qm.Derivative == derivativeRate {
+		frame, err = buildDerivativeFrame(frame, qm.Derivative)
+		if err != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+		}
+	}
+
+	if qm.Percentile != nil {
+		frame, err = buildPercentileFrame(frame, *qm.Percentile, time.Duration(qm.IntervalMs)*time.Millisecond)
+		if err != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+		}
+	}
+
+	if qm.Histogram {
+		buckets := qm.Buckets
+		if buckets == 0 {
+			buckets = defaultHistogramBuckets
+		}
+		frame, err = buildHistogramFrame(frame, buckets)
+		if err != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+		}
+	} else if qm.MaxPoints > 0 {
+		// Servers not supporting point limit return full history
+		frame = limitFramePoints(frame, qm.MaxPoints)
+	}
+
+	return backend.DataResponse{
+		Frames: data.Frames{frame},
+	}
+}
+
+// allDciQuery requests history of all DCIs of the object, returning one labeled series per DCI
+func (ds *NetXMSDatasource) allDciQuery(ctx context.Context, config *models.PluginSettings, timeRange backend.TimeRange, qm queryModel) backend.DataResponse {
+	client := ds.httpClient(ctx, config)
+	listURL := joinURL(config.ServerAddress, fmt.Sprintf("/v1/grafana/objects/%s/dci-list", qm.SourceObjectId))
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, listURL, nil, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
+	}
+
+	result, err := client.Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
+	}
+	body, err := readResponseBody(result.Body, config)
+	result.Body.Close()
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
+	}
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
+	}
+
+	var dciList struct {
+		Objects []struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"objects"`
+	}
+	if err := json.Unmarshal(body, &dciList); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse DCI list: %v", err))
+	}
+
+	dcis := dciList.Objects
+	var notices []data.Notice
+	if len(dcis) > maxObjectDcis {
+		notices = append(notices, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("Object has %d DCIs, only first %d are shown", len(dcis), maxObjectDcis),
+		})
+		dcis = dcis[:maxObjectDcis]
+	}
+
+	results := make([]backend.DataResponse, len(dcis))
+	semaphore := make(chan struct{}, maxConcurrentDciRequests)
+	var wg sync.WaitGroup
+	for i, dci := range dcis {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			dciQm := qm
+			dciQm.DciId = queryID(strconv.FormatInt(dci.Id, 10))
+			results[i] = ds.dciQuery(ctx, config, timeRange, dciQm)
+		}()
+	}
+	wg.Wait()
+
+	var response backend.DataResponse
+	for i, res := range results {
+		if res.Error != nil {
+			return res
+		}
+		for _, frame := range res.Frames {
+			for _, field := range frame.Fields {
+				if field.Type().Time() {
+					continue
+				}
+				if field.Labels == nil {
+					field.Labels = data.Labels{}
+				}
+				field.Labels["dci"] = dcis[i].Name
+			}
+			response.Frames = append(response.Frames, frame)
+		}
+	}
+	if len(notices) > 0 && len(response.Frames) > 0 {
+		response.Frames[0].AppendNotices(notices...)
+	}
+	return response
+}
+
+// buildDciFrame converts DCI history into time series frame. Values are stored
+// as numbers when all of them are numeric and as strings otherwise. Values
+// matching one of null sentinels, NaN and infinity are stored as nulls in
+// numeric field.
+func buildDciFrame(dciData dciValueResponse, nullValues []string) (*data.Frame, error) {
+	if slices.ContainsFunc(dciData.Values, dciValue.isAggregated) {
+		return buildDciBandFrame(dciData, nullValues)
+	}
+
+	frame := data.NewFrame(dciData.Description)
+
+	times := make([]time.Time, len(dciData.Values))
+
+	// First, try to parse all values as floats
+	isNumeric := true
+	hasNulls := false
+	hasStatus := false
+	floatValues := make([]*float64, len(dciData.Values))
+
+	for i, v := range dciData.Values {
+		t, err := time.Parse(time.RFC3339, v.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+		times[i] = t
+
+		if slices.Contains(nullValues, strings.TrimSpace(v.Value)) {
+			hasNulls = true
+		} else if val, err := strconv.ParseFloat(v.Value, 64); err != nil {
+			isNumeric = false
+		} else if !isFinite(val) {
+			// NaN and infinity can't be plotted, so they are kept as gaps
+			hasNulls = true
+		} else {
+			floatValues[i] = &val
+		}
+
+		if v.Status != "" {
+			hasStatus = true
+		}
+	}
+
+	switch {
+	case isNumeric && hasNulls:
+		// Missing points are kept as gaps in numeric series
+		frame.Fields = append(frame.Fields,
+			data.NewField("time", nil, times),
+			data.NewField("value", map[string]string{"unit": dciData.UnitName}, floatValues),
+		)
+	case isNumeric:
+		// All values are numeric, use float64 field
+		values := make([]float64, len(floatValues))
+		for i, v := range floatValues {
+			values[i] = *v
+		}
+		frame.Fields = append(frame.Fields,
+			data.NewField("time", nil, times),
+			data.NewField("value", map[string]string{"unit": dciData.UnitName}, values),
+		)
+	default:
+		// Some values are not numeric, use string field
+		stringValues := make([]string, len(dciData.Values))
+		for i, v := range dciData.Values {
+			stringValues[i] = v.Value
+		}
+		frame.Fields = append(frame.Fields,
+			data.NewField("time", nil, times),
+			data.NewField("value", nil, stringValues),
+		)
+	}
+
+	if hasStatus {
+		// Per-point threshold state allows coloring points in panels
+		statuses := make([]string, len(dciData.Values))
+		for i, v := range dciData.Values {
+			statuses[i] = v.Status
+		}
+		frame.Fields = append(frame.Fields, data.NewField("status", nil, statuses))
+	}
+
+	return frame, nil
+}
+
+// buildDciBandFrame converts aggregated DCI history into time series frame
+// with min, value and max fields, so panels can render bands around average.
+// Value field holds average, so transformations of DCI values apply to it.
+// Points without aggregates use their single value for all three fields.
+// Missing aggregates, null sentinels and non-numeric values are stored as nulls.
+func buildDciBandFrame(dciData dciValueResponse, nullValues []string) (*data.Frame, error) {
+	times := make([]time.Time, len(dciData.Values))
+	mins := make([]*float64, len(dciData.Values))
+	avgs := make([]*float64, len(dciData.Values))
+	maxs := make([]*float64, len(dciData.Values))
+	for i, v := range dciData.Values {
+		t, err := time.Parse(time.RFC3339, v.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+		times[i] = t
+		if v.isAggregated() {
+			mins[i], avgs[i], maxs[i] = v.Min, v.Avg, v.Max
+			continue
+		}
+		if slices.Contains(nullValues, strings.TrimSpace(v.Value)) {
+			continue
+		}
+		if val, err := strconv.ParseFloat(v.Value, 64); err == nil && isFinite(val) {
+			mins[i], avgs[i], maxs[i] = &val, &val, &val
+		}
+	}
+
+	labels := map[string]string{"unit": dciData.UnitName}
+	return data.NewFrame(dciData.Description,
+		data.NewField("time", nil, times),
+		data.NewField("min", labels, mins),
+		data.NewField("value", labels, avgs),
+		data.NewField("max", labels, maxs),
+	), nil
+}
+
+// transformsDciValues checks if query computes its result from DCI history
+// instead of returning history points as is
+func transformsDciValues(qm queryModel) bool {
+	return qm.Derivative == derivativeDelta || qm.Derivative == derivativeRate || qm.Percentile != nil || qm.Histogram
+}
+
+// limitFramePoints reduces time series frame to at most limit rows evenly
+// spread over the series, keeping its first and last row. Single row limit
+// keeps the newest row.
+func limitFramePoints(frame *data.Frame, limit int) *data.Frame {
+	rows := frame.Rows()
+	if rows <= limit {
+		return frame
+	}
+	indexes := make([]int, limit)
+	if limit == 1 {
+		timeField, _ := frame.FieldByName("time")
+		for i := range rows {
+			if timeField != nil && timeField.At(i).(time.Time).After(timeField.At(indexes[0]).(time.Time)) {
+				indexes[0] = i
+			}
+		}
+	} else {
+		step := float64(rows-1) / float64(limit-1)
+		for i := range indexes {
+			indexes[i] = int(math.Round(float64(i) * step))
+		}
+	}
+
+	limited := data.NewFrame(frame.Name)
+	limited.Meta = frame.Meta
+	for _, field := range frame.Fields {
+		sampled := data.NewFieldFromFieldType(field.Type(), limit)
+		sampled.Name, sampled.Labels, sampled.Config = field.Name, field.Labels, field.Config
+		for i, index := range indexes {
+			sampled.Set(i, field.At(index))
+		}
+		limited.Fields = append(limited.Fields, sampled)
+	}
+	return limited
+}
+
+// latestValueParameter limits DCI history to single value. History endpoint
+// returns values from newest to oldest, so the only value returned is the
+// latest one (see "NetXMS server API" in README).
+const latestValueParameter = "itemCount=1"
+
+// latestDciValue returns the newest of DCI values. Servers ignoring item count
+// return whole history, so value is picked by timestamp instead of position.
+func latestDciValue(values []dciValue) []dciValue {
+	if len(values) < 2 {
+		return values
+	}
+	latest := values[0]
+	latestTime, _ := time.Parse(time.RFC3339, latest.Timestamp)
+	for _, v := range values[1:] {
+		if t, err := time.Parse(time.RFC3339, v.Timestamp); err == nil && t.After(latestTime) {
+			latest, latestTime = v, t
+		}
+	}
+	return []dciValue{latest}
+}
+
+// buildDerivativeFrame converts counter-like DCI time series into differences
+// between consecutive values, or into per-second rates. Points are ordered by
+// time first and null values are skipped. Negative differences are caused by
+// counter reset or wrap, so such intervals produce no point.
+func buildDerivativeFrame(series *data.Frame, mode string) (*data.Frame, error) {
+	timeField, _ := series.FieldByName("time")
+	valueField, _ := series.FieldByName("value")
+	if timeField == nil || valueField == nil || !valueField.Type().Numeric() {
+		return nil, errors.New("derivative requires numeric DCI values")
+	}
+
+	type point struct {
+		time  time.Time
+		value float64
+	}
+	points := make([]point, 0, valueField.Len())
+	for i := range valueField.Len() {
+		if value, ok := valueField.ConcreteAt(i); ok {
+			points = append(points, point{time: timeField.At(i).(time.Time), value: value.(float64)})
+		}
+	}
+	slices.SortStableFunc(points, func(a, b point) int {
+		return a.time.Compare(b.time)
+	})
+
+	times := []time.Time{}
+	values := []float64{}
+	for i := 1; i < len(points); i++ {
+		delta := points[i].value - points[i-1].value
+		if delta < 0 {
+			continue
+		}
+		if mode == derivativeRate {
+			seconds := points[i].time.Sub(points[i-1].time).Seconds()
+			if seconds <= 0 {
+				continue
+			}
+			delta /= seconds
+		}
+		times = append(times, points[i].time)
+		values = append(values, delta)
+	}
+
+	return data.NewFrame(series.Name,
+		data.NewField("time", nil, times),
+		data.NewField("value", valueField.Labels, values),
+	), nil
+}
+
+// buildPercentileFrame aggregates DCI time series into given percentile of
+// values within each interval, or within whole series if interval is not set.
+// Percentile is interpolated linearly between closest ranks, null values are skipped.
+func buildPercentileFrame(series *data.Frame, percentile float64, interval time.Duration) (*data.Frame, error) {
+	timeField, _ := series.FieldByName("time")
+	valueField, _ := series.FieldByName("value")
+	if timeField == nil || valueField == nil || !valueField.Type().Numeric() {
+		return nil, errors.New("percentile requires numeric DCI values")
+	}
+
+	buckets := make(map[time.Time][]float64)
+	for i := range valueField.Len() {
+		value, ok := valueField.ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		var bucket time.Time
+		if interval > 0 {
+			bucket = timeField.At(i).(time.Time).Truncate(interval)
+		}
+		buckets[bucket] = append(buckets[bucket], value.(float64))
+	}
+
+	times := slices.SortedFunc(maps.Keys(buckets), func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+	values := make([]float64, len(times))
+	for i, bucket := range times {
+		values[i] = percentileOf(buckets[bucket], percentile)
+	}
+	if interval <= 0 && len(times) > 0 {
+		// Whole series is single bucket, reported at its latest point
+		latest := timeField.At(0).(time.Time)
+		for i := range timeField.Len() {
+			if t := timeField.At(i).(time.Time); t.After(latest) {
+				latest = t
+			}
+		}
+		times[0] = latest
+	}
+
+	return data.NewFrame(series.Name,
+		data.NewField("time", nil, times),
+		data.NewField("value", valueField.Labels, values),
+	), nil
+}
+
+// percentileOf returns percentile (0-100) of non-empty value list
+func percentileOf(values []float64, percentile float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// buildHistogramFrame converts DCI time series frame into histogram frame with
+// equal width buckets between minimum and maximum value. Null values are skipped.
+func buildHistogramFrame(series *data.Frame, buckets int) (*data.Frame, error) {
+	valueField, _ := series.FieldByName("value")
+	if valueField == nil || !valueField.Type().Numeric() {
+		return nil, errors.New("histogram requires numeric DCI values")
+	}
+
+	values := make([]float64, 0, valueField.Len())
+	for i := range valueField.Len() {
+		if value, ok := valueField.ConcreteAt(i); ok {
+			values = append(values, value.(float64))
+		}
+	}
+
+	xMin := []float64{}
+	xMax := []float64{}
+	counts := []int64{}
+	if len(values) > 0 {
+		low, high := slices.Min(values), slices.Max(values)
+		width := (high - low) / float64(buckets)
+		if width == 0 {
+			// All values are equal, single bucket holds all of them
+			buckets, width = 1, 1
+		}
+		counts = make([]int64, buckets)
+		for i := range buckets {
+			xMin = append(xMin, low+float64(i)*width)
+			xMax = append(xMax, low+float64(i+1)*width)
+		}
+		for _, value := range values {
+			bucket := min(int((value-low)/width), buckets-1)
+			counts[bucket]++
+		}
+	}
+
+	return data.NewFrame(series.Name,
+		data.NewField("xMin", nil, xMin),
+		data.NewField("xMax", nil, xMax),
+		data.NewField("count", nil, counts),
+	), nil
+}
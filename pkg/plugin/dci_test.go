@@ -0,0 +1,756 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+func TestDciServerAggregation(t *testing.T) {
+	var function string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		function = r.URL.Query().Get("function")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[{"timestamp":"2026-01-01T00:00:00Z","value":"1.5"}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "serverAggregation": "avg"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if function != "avg" {
+		t.Errorf("Expected function=avg to be forwarded, got: %q", function)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "serverAggregation": "median"}`)
+	if response.Error == nil {
+		t.Error("Expected error for unsupported aggregation function")
+	}
+}
+
+func TestDciInstance(t *testing.T) {
+	var instance string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		instance = r.URL.Query().Get("instance")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"Inbound traffic","values":[{"timestamp":"2026-01-01T00:00:00Z","value":"10"}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "instance": "eth0 & lo"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if instance != "eth0 & lo" {
+		t.Errorf("Expected instance to be forwarded, got: %q", instance)
+	}
+	if len(response.Frames) != 1 {
+		t.Errorf("Expected single series, got: %d", len(response.Frames))
+	}
+}
+
+func TestDciZeroTimeRangeFallback(t *testing.T) {
+	var timeFrom, timeTo time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeFrom, _ = time.Parse(serverTimeLayout, r.URL.Query().Get("timeFrom"))
+		timeTo, _ = time.Parse(serverTimeLayout, r.URL.Query().Get("timeTo"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[]}`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "defaultTimeRange": 600}`),
+	}
+	ds := newTestDatasource(t, settings)
+
+	resp, err := ds.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+		},
+		Queries: []backend.DataQuery{
+			{RefID: "A", QueryType: "dciValues", JSON: []byte(`{"sourceObjectId": "1", "dciId": "2"}`)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Responses["A"].Error != nil {
+		t.Fatalf("Expected no error, got: %v", resp.Responses["A"].Error)
+	}
+
+	now := time.Now()
+	if timeTo.Before(now.Add(-5*time.Second)) || timeTo.After(now) {
+		t.Errorf("Expected timeTo to be now, got: %v", timeTo)
+	}
+	if window := timeTo.Sub(timeFrom); window != 600*time.Second {
+		t.Errorf("Expected configured 600s fallback window, got: %v", window)
+	}
+}
+
+func TestDciValuesWithStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[` +
+			`{"timestamp":"2026-01-01T00:00:00Z","value":"10","status":"Normal"},` +
+			`{"timestamp":"2026-01-01T00:01:00Z","value":"95","status":"Critical"}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if len(frame.Fields) != 3 || frame.Fields[2].Name != "status" {
+		t.Fatalf("Expected time, value and status fields, got: %d fields", len(frame.Fields))
+	}
+	if status, _ := frame.Fields[2].At(1).(string); status != "Critical" {
+		t.Errorf("Expected Critical status, got: %q", status)
+	}
+}
+
+func TestBuildDciFrameWithoutStatus(t *testing.T) {
+	frame, err := buildDciFrame(dciValueResponse{
+		Description: "CPU",
+		Values:      []dciValue{{Timestamp: "2026-01-01T00:00:00Z", Value: "10"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frame.Fields) != 2 {
+		t.Errorf("Expected only time and value fields, got: %d", len(frame.Fields))
+	}
+}
+
+func TestAllDcisQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/dci-list") {
+			_, _ = w.Write([]byte(`{"objects":[{"id":10,"name":"CPU"},{"id":11,"name":"Memory"},{"id":12,"name":"Disk"}]}`))
+			return
+		}
+		parts := strings.Split(r.URL.Path, "/")
+		dciID := parts[len(parts)-2]
+		_, _ = w.Write([]byte(`{"description":"DCI ` + dciID + `","values":[{"timestamp":"2026-01-01T00:00:00Z","value":"` + dciID + `"}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "allDcis": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 3 {
+		t.Fatalf("Expected one frame per DCI, got: %d", len(response.Frames))
+	}
+	for i, name := range []string{"CPU", "Memory", "Disk"} {
+		field := response.Frames[i].Fields[1]
+		if field.Labels["dci"] != name {
+			t.Errorf("Expected frame %d to be labeled %q, got: %q", i, name, field.Labels["dci"])
+		}
+	}
+}
+
+func TestAllDcisQueryLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/dci-list") {
+			dcis := make([]string, 0, maxObjectDcis+5)
+			for i := 0; i < maxObjectDcis+5; i++ {
+				dcis = append(dcis, fmt.Sprintf(`{"id":%d,"name":"DCI %d"}`, i+1, i+1))
+			}
+			_, _ = w.Write([]byte(`{"objects":[` + strings.Join(dcis, ",") + `]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"description":"DCI","values":[]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "allDcis": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != maxObjectDcis {
+		t.Errorf("Expected %d frames, got: %d", maxObjectDcis, len(response.Frames))
+	}
+	if len(response.Frames[0].Meta.Notices) == 0 {
+		t.Error("Expected notice about truncated DCI list")
+	}
+}
+
+func TestDciQueryMissingFields(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"missing object id", `{"dciId": "2"}`, "sourceObjectId is required"},
+		{"missing DCI id", `{"sourceObjectId": "1"}`, "dciId is required"},
+		{"non-numeric DCI id", `{"sourceObjectId": "1", "dciId": "cpu"}`, "dciId must be numeric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := runTestQuery(t, newTestSettings(server.URL), "dciValues", tt.query)
+			if response.Error == nil || response.Error.Error() != tt.expected {
+				t.Errorf("Expected error %q, got: %v", tt.expected, response.Error)
+			}
+			if response.Status != backend.StatusBadRequest {
+				t.Errorf("Expected bad request status, got: %v", response.Status)
+			}
+		})
+	}
+	if calls != 0 {
+		t.Errorf("Expected no upstream requests for invalid queries, got: %d", calls)
+	}
+}
+
+func TestBuildDciFrameNullSentinels(t *testing.T) {
+	frame, err := buildDciFrame(dciValueResponse{
+		Description: "CPU",
+		Values: []dciValue{
+			{Timestamp: "2026-01-01T00:00:00Z", Value: "10"},
+			{Timestamp: "2026-01-01T00:01:00Z", Value: "N/A"},
+			{Timestamp: "2026-01-01T00:02:00Z", Value: "-"},
+			{Timestamp: "2026-01-01T00:03:00Z", Value: "12.5"},
+		},
+	}, models.DefaultNullValues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := frame.Fields[1]
+	if field.Type() != data.FieldTypeNullableFloat64 {
+		t.Fatalf("Expected nullable float field, got: %v", field.Type())
+	}
+	if field.At(1).(*float64) != nil || field.At(2).(*float64) != nil {
+		t.Error("Expected sentinel values to become nulls")
+	}
+	if v := field.At(3).(*float64); v == nil || *v != 12.5 {
+		t.Errorf("Expected 12.5, got: %v", v)
+	}
+
+	// Without configured sentinels values are kept as strings
+	frame, err = buildDciFrame(dciValueResponse{
+		Values: []dciValue{{Timestamp: "2026-01-01T00:00:00Z", Value: "N/A"}},
+	}, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Fields[1].Type() != data.FieldTypeString {
+		t.Errorf("Expected string field, got: %v", frame.Fields[1].Type())
+	}
+}
+
+func TestDciQueryServerTimezone(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[]}`))
+	}))
+	defer server.Close()
+
+	timeRange := backend.TimeRange{
+		From: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC),
+	}
+	qm := queryModel{SourceObjectId: "1", DciId: "2"}
+	ds := &NetXMSDatasource{}
+
+	config, err := models.LoadPluginSettings(backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "serverTimezone": "Europe/Riga"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := ds.dciQuery(context.Background(), config, timeRange, qm); response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if query.Get("timeFrom") != "2026-01-01T12:00:00" || query.Get("timeTo") != "2026-01-01T13:00:00" {
+		t.Errorf("Expected bounds in server time zone, got: %s - %s", query.Get("timeFrom"), query.Get("timeTo"))
+	}
+
+	config.TimeZone = ""
+	if response := ds.dciQuery(context.Background(), config, timeRange, qm); response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if query.Get("timeFrom") != "2026-01-01T10:00:00" || query.Get("timeTo") != "2026-01-01T11:00:00" {
+		t.Errorf("Expected bounds in UTC without time zone, got: %s - %s", query.Get("timeFrom"), query.Get("timeTo"))
+	}
+}
+
+func TestDciListErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"reason":"Access denied"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		expected string
+	}{
+		{"upstream error", "dcis?objectId=1", http.StatusBadGateway, "Request error: Access denied"},
+		{"missing object id", "dcis", http.StatusBadRequest, "missing objectId parameter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := callTestResource(t, newTestSettings(server.URL), tt.path)
+			if response.Status != tt.status {
+				t.Errorf("Expected status %d, got: %d", tt.status, response.Status)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(response.Body, &body); err != nil {
+				t.Fatalf("Expected JSON error body, got: %s", response.Body)
+			}
+			if body["error"] != tt.expected {
+				t.Errorf("Expected error %q, got: %q", tt.expected, body["error"])
+			}
+		})
+	}
+}
+
+func TestDciHistogram(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[` +
+			`{"timestamp":"2026-01-01T00:00:00Z","value":"0"},` +
+			`{"timestamp":"2026-01-01T00:01:00Z","value":"15"},` +
+			`{"timestamp":"2026-01-01T00:02:00Z","value":"N/A"},` +
+			`{"timestamp":"2026-01-01T00:03:00Z","value":"40"},` +
+			`{"timestamp":"2026-01-01T00:04:00Z","value":"90"},` +
+			`{"timestamp":"2026-01-01T00:05:00Z","value":"100"}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "histogram": true, "buckets": 4}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 4 {
+		t.Fatalf("Expected 4 buckets, got: %d", frame.Rows())
+	}
+	countField, _ := frame.FieldByName("count")
+	var total int64
+	for i := range countField.Len() {
+		total += countField.At(i).(int64)
+	}
+	if total != 5 {
+		t.Errorf("Expected bucket counts to sum to 5 non-null points, got: %d", total)
+	}
+	if last, _ := countField.At(3).(int64); last != 2 {
+		t.Errorf("Expected maximum value in last bucket, got: %d values", last)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "allDcis": true, "histogram": true}`)
+	if response.Error == nil || !strings.Contains(response.Error.Error(), "allDcis") {
+		t.Errorf("Expected histogram with allDcis to be rejected, got: %v", response.Error)
+	}
+}
+
+func TestDciLastValue(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU","values":[` +
+			`{"timestamp":"2026-01-01T00:01:00Z","value":"20"},` +
+			`{"timestamp":"2026-01-01T00:02:00Z","value":"30"},` +
+			`{"timestamp":"2026-01-01T00:00:00Z","value":"10"}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "lastValue": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if query.Get("itemCount") != "1" {
+		t.Errorf("Expected only latest point to be requested, got itemCount: %q", query.Get("itemCount"))
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 1 {
+		t.Fatalf("Expected single row, got: %d", frame.Rows())
+	}
+	if value, _ := frame.Fields[1].At(0).(float64); value != 30 {
+		t.Errorf("Expected latest value 30, got: %v", value)
+	}
+}
+
+func TestDciValueFieldName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU usage","unitName":"%","values":[{"timestamp":"2024-01-01T00:00:00Z","value":"42"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"default", `{"sourceObjectId": "1", "dciId": "2"}`, "value"},
+		{"custom", `{"sourceObjectId": "1", "dciId": "2", "seriesNameFrom": "custom", "seriesName": "cpu"}`, "cpu"},
+		{"description", `{"sourceObjectId": "1", "dciId": "2", "seriesNameFrom": "description"}`, "CPU usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := runTestQuery(t, newTestSettings(server.URL), "dciValues", tt.query)
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got: %v", response.Error)
+			}
+			if name := response.Frames[0].Fields[1].Name; name != tt.expected {
+				t.Errorf("Expected value field %q, got: %q", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestDciDerivative(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Newest points first, counter is reset between 00:02 and 00:03
+		_, _ = w.Write([]byte(`{"description":"Bytes in","values":[` +
+			`{"timestamp":"2024-01-01T00:04:00Z","value":"600"},` +
+			`{"timestamp":"2024-01-01T00:03:00Z","value":"60"},` +
+			`{"timestamp":"2024-01-01T00:02:00Z","value":"N/A"},` +
+			`{"timestamp":"2024-01-01T00:01:00Z","value":"1800"},` +
+			`{"timestamp":"2024-01-01T00:00:00Z","value":"600"}]}`))
+	}))
+	defer server.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		mode   string
+		times  []time.Time
+		values []float64
+	}{
+		{"delta", []time.Time{start.Add(time.Minute), start.Add(4 * time.Minute)}, []float64{1200, 540}},
+		{"rate", []time.Time{start.Add(time.Minute), start.Add(4 * time.Minute)}, []float64{20, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			response := runTestQuery(t, newTestSettings(server.URL), "dciValues",
+				`{"sourceObjectId": "1", "dciId": "2", "derivative": "`+tt.mode+`"}`)
+			if response.Error != nil {
+				t.Fatalf("Expected no error, got: %v", response.Error)
+			}
+			frame := response.Frames[0]
+			if frame.Rows() != len(tt.values) {
+				t.Fatalf("Expected %d points without counter reset, got: %d", len(tt.values), frame.Rows())
+			}
+			for i := range tt.values {
+				if !frame.Fields[0].At(i).(time.Time).Equal(tt.times[i]) || frame.Fields[1].At(i) != tt.values[i] {
+					t.Errorf("Unexpected point %d: %v %v", i, frame.Fields[0].At(i), frame.Fields[1].At(i))
+				}
+			}
+		})
+	}
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "derivative": "integral"}`)
+	if response.Error == nil || response.Error.Error() != "unsupported derivative: integral" {
+		t.Errorf("Expected unsupported derivative error, got: %v", response.Error)
+	}
+}
+
+func TestDciTypeResource(t *testing.T) {
+	var requestPath string
+	responses := map[string]string{
+		"10": `{"id":10,"dataType":"FLOAT"}`,
+		"11": `{"id":11,"dataType":4}`,
+		"12": `{"id":12,"dciType":"table"}`,
+		"13": `{"id":13,"dataType":"COUNTER64"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]]))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		dciID    string
+		expected dciTypeInfo
+	}{
+		{"10", dciTypeInfo{DataType: "float", Kind: "numeric"}},
+		{"11", dciTypeInfo{DataType: "string", Kind: "string"}},
+		{"12", dciTypeInfo{DataType: "table", Kind: "table"}},
+		{"13", dciTypeInfo{DataType: "counter64", Kind: "numeric"}},
+	}
+	for _, tt := range tests {
+		response := callTestResource(t, newTestSettings(server.URL), "dciType?objectId=5&dciId="+tt.dciID)
+		if response.Status != http.StatusOK {
+			t.Fatalf("Expected status 200, got: %d %s", response.Status, string(response.Body))
+		}
+		if requestPath != "/v1/objects/5/data-collection/"+tt.dciID {
+			t.Errorf("Unexpected request path: %s", requestPath)
+		}
+		var info dciTypeInfo
+		if err := json.Unmarshal(response.Body, &info); err != nil {
+			t.Fatal(err)
+		}
+		if info != tt.expected {
+			t.Errorf("Expected %+v for DCI %s, got: %+v", tt.expected, tt.dciID, info)
+		}
+	}
+
+	response := callTestResource(t, newTestSettings(server.URL), "dciType?objectId=5")
+	if response.Status != http.StatusBadRequest || !strings.Contains(string(response.Body), "missing dciId parameter") {
+		t.Errorf("Expected missing dciId error, got: %d %s", response.Status, string(response.Body))
+	}
+}
+
+func TestDciPercentile(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	values := make([]string, 0, 20)
+	for i := range 20 {
+		// Values 1..20 in random order, one per minute
+		value := (i*7)%20 + 1
+		values = append(values, fmt.Sprintf(`{"timestamp":"%s","value":"%d"}`, start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339), value))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"Latency","values":[` + strings.Join(values, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "percentile": 95}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 1 {
+		t.Fatalf("Expected single value for whole series, got: %d", frame.Rows())
+	}
+	if value := frame.Fields[1].At(0).(float64); math.Abs(value-19.05) > 1e-9 {
+		t.Errorf("Expected p95 of 1..20 to be 19.05, got: %v", value)
+	}
+	if !frame.Fields[0].At(0).(time.Time).Equal(start.Add(19 * time.Minute)) {
+		t.Errorf("Expected value at latest point, got: %v", frame.Fields[0].At(0))
+	}
+
+	// 10 minute buckets hold values of first and second half of series
+	response = runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "percentile": 50, "intervalMs": 600000}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame = response.Frames[0]
+	if frame.Rows() != 2 || !frame.Fields[0].At(1).(time.Time).Equal(start.Add(10*time.Minute)) {
+		t.Fatalf("Expected two 10 minute buckets, got: %v", frame.Fields[0])
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "percentile": 101}`)
+	if response.Error == nil || response.Error.Error() != "percentile must be between 0 and 100" {
+		t.Errorf("Expected percentile validation error, got: %v", response.Error)
+	}
+}
+
+func TestDciAggregatedBands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU usage","unitName":"%","values":[` +
+			`{"timestamp":"2024-01-01T00:00:00Z","min":10,"avg":25.5,"max":40},` +
+			`{"timestamp":"2024-01-01T00:05:00Z","min":12,"avg":30,"max":61}]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if len(frame.Fields) != 4 || frame.Rows() != 2 {
+		t.Fatalf("Expected time and 3 value fields with 2 rows, got: %d fields, %d rows", len(frame.Fields), frame.Rows())
+	}
+	expected := map[string][]float64{
+		"min":   {10, 12},
+		"value": {25.5, 30},
+		"max":   {40, 61},
+	}
+	for name, values := range expected {
+		field, _ := frame.FieldByName(name)
+		if field == nil {
+			t.Fatalf("Expected %s field", name)
+		}
+		for i, value := range values {
+			if v := field.At(i).(*float64); v == nil || *v != value {
+				t.Errorf("Unexpected %s value %d: %v", name, i, v)
+			}
+		}
+		if field.Labels["unit"] != "%" {
+			t.Errorf("Expected unit label on %s field, got: %v", name, field.Labels)
+		}
+	}
+}
+
+func TestDciAggregatedBandsTransforms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"description":"CPU usage","unitName":"%","values":[` +
+			`{"timestamp":"2024-01-01T00:00:00Z","min":10,"avg":20,"max":40},` +
+			`{"timestamp":"2024-01-01T00:05:00Z","value":"N/A"},` +
+			`{"timestamp":"2024-01-01T00:10:00Z","value":"50"}]}`))
+	}))
+	defer server.Close()
+
+	// Points without aggregates keep their value, null sentinels are applied
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "seriesNameFrom": "custom", "seriesName": "CPU"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	field, _ := response.Frames[0].FieldByName("CPU")
+	if field == nil {
+		t.Fatalf("Expected average field renamed by series name, got: %v", response.Frames[0].Fields)
+	}
+	if v := field.At(1).(*float64); v != nil {
+		t.Errorf("Expected null sentinel to become null, got: %v", *v)
+	}
+	if v := field.At(2).(*float64); v == nil || *v != 50 {
+		t.Errorf("Expected plain point value 50, got: %v", v)
+	}
+	if maxField, _ := response.Frames[0].FieldByName("max"); maxField == nil || *maxField.At(2).(*float64) != 50 {
+		t.Error("Expected plain point value in max field")
+	}
+
+	for _, query := range []string{`"derivative": "delta"`, `"percentile": 95`, `"histogram": true`} {
+		response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", `+query+`}`)
+		if response.Error != nil {
+			t.Errorf("%s: expected transform of average values, got: %v", query, response.Error)
+		}
+	}
+}
+
+func TestDciMaxPoints(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var maxPoints string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxPoints = r.URL.Query().Get("maxPoints")
+		// Server ignores the limit and returns full history of 10 points
+		values := make([]string, 10)
+		for i := range values {
+			values[i] = fmt.Sprintf(`{"timestamp":"%s","value":"%d"}`, start.Add(time.Duration(i)*time.Minute).Format(time.RFC3339), i)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"values":[` + strings.Join(values, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "maxPoints": 4}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if maxPoints != "4" {
+		t.Errorf("Expected maxPoints forwarded to server, got: %q", maxPoints)
+	}
+	values := response.Frames[0].Fields[1]
+	var got []float64
+	for i := range values.Len() {
+		got = append(got, values.At(i).(float64))
+	}
+	if !slices.Equal(got, []float64{0, 3, 6, 9}) {
+		t.Errorf("Expected 4 evenly spread points, got: %v", got)
+	}
+
+	runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2"}`)
+	if maxPoints != "" {
+		t.Errorf("Expected no maxPoints by default, got: %q", maxPoints)
+	}
+
+	// Derivative is computed from full history, and only its result is limited
+	response = runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", "maxPoints": 4, "derivative": "delta"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if maxPoints != "" {
+		t.Errorf("Expected maxPoints not forwarded for transformed values, got: %q", maxPoints)
+	}
+	values = response.Frames[0].Fields[1]
+	if values.Len() != 4 {
+		t.Fatalf("Expected 4 points, got: %d", values.Len())
+	}
+	for i := range values.Len() {
+		if v, _ := values.ConcreteAt(i); v != 1.0 {
+			t.Errorf("Expected delta of consecutive points, got: %v", v)
+		}
+	}
+}
+
+func TestDciSeriesNameFrom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/history") {
+			_, _ = w.Write([]byte(`{"description":"CPU usage","unitName":"%","values":[{"timestamp":"2026-01-01T00:00:00Z","value":"12"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1,"name":"server1"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`"seriesNameFrom": "description"`, "CPU usage"},
+		{`"seriesNameFrom": "objectName"`, "server1"},
+		{`"seriesNameFrom": "custom", "seriesName": "Load"`, "Load"},
+		{`"seriesNameFrom": "label", "seriesNameLabel": "unit"`, "%"},
+		{`"seriesNameFrom": "label", "seriesNameLabel": "missing"`, "CPU usage"},
+	}
+	for _, tt := range tests {
+		response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", `+tt.query+`}`)
+		if response.Error != nil {
+			t.Fatalf("%s: expected no error, got: %v", tt.query, response.Error)
+		}
+		frame := response.Frames[0]
+		if frame.Name != tt.want {
+			t.Errorf("%s: expected frame name %q, got: %q", tt.query, tt.want, frame.Name)
+		}
+		if config := frame.Fields[1].Config; config == nil || config.DisplayNameFromDS != tt.want {
+			t.Errorf("%s: expected series display name %q, got: %v", tt.query, tt.want, config)
+		}
+		if name := frame.Fields[1].Name; name != tt.want {
+			t.Errorf("%s: expected value field %q, got: %q", tt.query, tt.want, name)
+		}
+	}
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2"}`)
+	if frame := response.Frames[0]; frame.Name != "CPU usage" || frame.Fields[1].Config != nil {
+		t.Errorf("Expected default naming unchanged, got: %q, %v", frame.Name, frame.Fields[1].Config)
+	}
+
+	for _, query := range []string{
+		`"seriesNameFrom": "custom"`,
+		`"seriesNameFrom": "label"`,
+		`"seriesNameFrom": "unit"`,
+		`"allDcis": true, "seriesNameFrom": "objectName"`,
+		`"allDcis": true, "seriesNameFrom": "custom", "seriesName": "Load"`,
+	} {
+		response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2", `+query+`}`)
+		if response.Error == nil {
+			t.Errorf("%s: expected validation error", query)
+		}
+	}
+}
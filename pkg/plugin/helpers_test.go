@@ -0,0 +1,170 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// mockAlarmResponse creates a test server that returns mock alarm data
+func mockAlarmResponse() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify request headers
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Mock alarm response
+		alarms := []alarmResponse{
+			{
+				Id:         1,
+				Severity:   "Critical",
+				State:      "Active",
+				Source:     "Test Source",
+				Message:    "Test Alarm",
+				Count:      1,
+				AckBy:      "Test User",
+				Created:    alarmTime{time.Now()},
+				LastChange: alarmTime{time.Now()},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(alarms)
+	}))
+}
+
+// newTestSettings creates datasource settings pointing to given server
+func newTestSettings(serverURL string) backend.DataSourceInstanceSettings {
+	return backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + serverURL + `"}`),
+		DecryptedSecureJSONData: map[string]string{
+			"apiKey": "test-key",
+		},
+	}
+}
+
+// newTestDatasource creates datasource instance for given settings
+func newTestDatasource(t *testing.T, settings backend.DataSourceInstanceSettings) *NetXMSDatasource {
+	t.Helper()
+	instance, err := NewDatasource(context.Background(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, ok := instance.(*NetXMSDatasource)
+	if !ok {
+		t.Fatal("Failed to type assert instance to *Datasource")
+	}
+	return ds
+}
+
+// runTestQuery executes single query of given type against new datasource instance
+func runTestQuery(t *testing.T, settings backend.DataSourceInstanceSettings, queryType string, queryJSON string) backend.DataResponse {
+	t.Helper()
+	return runDatasourceQuery(t, newTestDatasource(t, settings), settings, queryType, queryJSON)
+}
+
+// runDatasourceQuery executes single query of given type against existing datasource instance
+func runDatasourceQuery(t *testing.T, ds *NetXMSDatasource, settings backend.DataSourceInstanceSettings, queryType string, queryJSON string) backend.DataResponse {
+	t.Helper()
+	resp, err := ds.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+		},
+		Queries: []backend.DataQuery{
+			{
+				RefID:     "A",
+				QueryType: queryType,
+				JSON:      []byte(queryJSON),
+				TimeRange: backend.TimeRange{
+					From: time.Now().Add(-time.Hour),
+					To:   time.Now(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Responses["A"]
+}
+
+// testObjectList mirrors the name : id list returned by resource endpoints
+type testObjectList struct {
+	Objects []struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"objects"`
+}
+
+// callTestResource executes resource request against the datasource
+func callTestResource(t *testing.T, settings backend.DataSourceInstanceSettings, path string) *backend.CallResourceResponse {
+	t.Helper()
+	return callTestResourceAs(t, settings, nil, http.MethodGet, path, nil)
+}
+
+// callTestResourceAs calls resource endpoint on behalf of given Grafana user
+func callTestResourceAs(t *testing.T, settings backend.DataSourceInstanceSettings, user *backend.User, method string, path string, body []byte) *backend.CallResourceResponse {
+	t.Helper()
+	ds := newTestDatasource(t, settings)
+
+	var response *backend.CallResourceResponse
+	err := ds.CallResource(context.Background(), &backend.CallResourceRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &settings,
+			User:                       user,
+		},
+		Method: method,
+		Path:   strings.SplitN(path, "?", 2)[0],
+		URL:    path,
+		Body:   body,
+	}, backend.CallResourceResponseSenderFunc(func(res *backend.CallResourceResponse) error {
+		response = res
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response == nil {
+		t.Fatal("Expected resource response")
+	}
+	return response
+}
+
+// mockAlarmListServer creates a test server returning alarms with given messages
+func mockAlarmListServer(messages ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alarms := make([]alarmResponse, len(messages))
+		for i, message := range messages {
+			alarms[i] = alarmResponse{Id: int32(i + 1), Severity: "Major", Message: message}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(alarms)
+	}))
+}
+
+// dnsFailureTransport fails dialing with given resolver errors, connecting
+// to address once errors are used up
+func dnsFailureTransport(address string, dials *int, failures ...*net.DNSError) *http.Transport {
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			*dials++
+			if *dials <= len(failures) {
+				return nil, failures[*dials-1]
+			}
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		},
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
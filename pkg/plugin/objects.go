@@ -0,0 +1,683 @@
+package plugin
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+// naturalLess compares strings treating digit sequences as numbers, so "Node2" sorts before "Node10"
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			startA, startB := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			numA := strings.TrimLeft(a[startA:i], "0")
+			numB := strings.TrimLeft(b[startB:j], "0")
+			if len(numA) != len(numB) {
+				return len(numA) < len(numB)
+			}
+			if numA != numB {
+				return numA < numB
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return a[i] < b[j]
+		}
+		i++
+		j++
+	}
+	return len(a)-i < len(b)-j
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// addObjectPath prefixes object name with path of its parent containers
+// ("Site/Rack/Node"), so objects with same name can be told apart. Path is
+// given by server either as string or as list of container names.
+func addObjectPath(object map[string]any) {
+	name, ok := object["name"].(string)
+	if !ok {
+		return
+	}
+	var parents []string
+	switch path := object["path"].(type) {
+	case string:
+		if path = strings.Trim(path, "/"); path != "" {
+			parents = append(parents, path)
+		}
+	case []any:
+		for _, parent := range path {
+			if parentName, ok := parent.(string); ok && parentName != "" {
+				parents = append(parents, parentName)
+			}
+		}
+	}
+	if len(parents) > 0 {
+		object["name"] = strings.Join(append(parents, name), "/")
+	}
+}
+
+// handleObjectList returns objects matching filter, requesting object paths
+// from server when they are shown in labels
+func (ds *NetXMSDatasource) handleObjectList(filter string, rw http.ResponseWriter, req *http.Request) {
+	path := "/v1/grafana/object-list?filter=" + filter
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	if config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings); err == nil && config.ObjectPaths {
+		path += "&includePath=true"
+	}
+	ds.handleQuery(path, rw, req)
+}
+
+func (ds *NetXMSDatasource) handleAlarmObjects(rw http.ResponseWriter, req *http.Request) {
+	ds.handleObjectList("alarm", rw, req)
+}
+
+func (ds *NetXMSDatasource) handleDciObjects(rw http.ResponseWriter, req *http.Request) {
+	ds.handleObjectList("dci", rw, req)
+}
+
+// handleSummaryTables returns summary tables, only those applicable to given object if objectId is set
+func (ds *NetXMSDatasource) handleSummaryTables(rw http.ResponseWriter, req *http.Request) {
+	if !req.URL.Query().Has("objectId") {
+		ds.handleQuery("/v1/grafana/summary-table-list", rw, req)
+		return
+	}
+	objectID, ok := objectIDParam(rw, req)
+	if !ok {
+		return
+	}
+	ds.handleQuery("/v1/grafana/summary-table-list?objectId="+objectID, rw, req)
+}
+
+func (ds *NetXMSDatasource) handleSummaryTableObjects(rw http.ResponseWriter, req *http.Request) {
+	ds.handleObjectList("summary", rw, req)
+}
+
+func (ds *NetXMSDatasource) handleObjectQueries(rw http.ResponseWriter, req *http.Request) {
+	ds.handleQuery("/v1/grafana/query-list", rw, req)
+}
+
+func (ds *NetXMSDatasource) handleObjectQueryObjects(rw http.ResponseWriter, req *http.Request) {
+	ds.handleObjectList("query", rw, req)
+}
+
+// objectIDParam reads numeric objectId request parameter, writing bad request response if it is missing or invalid
+func objectIDParam(rw http.ResponseWriter, req *http.Request) (string, bool) {
+	return idParam(rw, req, "objectId")
+}
+
+// idParam reads numeric id request parameter with given name, writing bad request response if it is missing or invalid
+func idParam(rw http.ResponseWriter, req *http.Request, name string) (string, bool) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("missing %s parameter", name))
+		return "", false
+	}
+	id, err := parseID(value)
+	if err != nil {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("%s must be numeric", name))
+		return "", false
+	}
+	return strconv.FormatInt(id, 10), true
+}
+
+// handleInterfaces returns interfaces of given node with their index and administrative/operational state
+func (ds *NetXMSDatasource) handleInterfaces(rw http.ResponseWriter, req *http.Request) {
+	objectID, ok := objectIDParam(rw, req)
+	if !ok {
+		return
+	}
+	path := fmt.Sprintf("/v1/grafana/objects/%s/interface-list", objectID)
+	ds.handleQuery(path, rw, req)
+}
+
+// objectRef is object id and name as returned in server object lists
+type objectRef struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// objectListFilters are object list filters supported by server
+var objectListFilters = []string{"alarm", "dci", "summary", "query"}
+
+// handleResolveObject returns all objects with given name, for chaining
+// template variables selecting object by name. Filter selects object list
+// as in object list resources ("alarm", "dci", "summary" or "query").
+func (ds *NetXMSDatasource) handleResolveObject(rw http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	if name == "" {
+		writeJSONError(rw, http.StatusBadRequest, "missing name parameter")
+		return
+	}
+	filter := req.URL.Query().Get("filter")
+	if !slices.Contains(objectListFilters, filter) {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("filter must be one of: %s", strings.Join(objectListFilters, ", ")))
+		return
+	}
+
+	_, body, ok := ds.fetchResource("/v1/grafana/object-list?filter="+filter, rw, req)
+	if !ok {
+		return
+	}
+
+	var objectList struct {
+		Objects []objectRef `json:"objects"`
+	}
+	if err := json.Unmarshal(body, &objectList); err != nil {
+		writeJSONError(rw, http.StatusBadGateway, fmt.Sprintf("failed to parse object list: %v", err))
+		return
+	}
+
+	// Names are not unique, so all matching objects are returned
+	matches := []objectRef{}
+	for _, obj := range objectList.Objects {
+		if obj.Name == name {
+			matches = append(matches, obj)
+		}
+	}
+	response, err := json.Marshal(map[string][]objectRef{"objects": matches})
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to marshal response")
+		return
+	}
+	writeJSONResponse(rw, response)
+}
+
+type objectStatusResponse struct {
+	Id     int64  `json:"Id"`
+	Name   string `json:"Name"`
+	Status int32  `json:"Status"`
+	// Availability percentage, provided only by servers supporting it
+	Availability *float64 `json:"Availability,omitempty"`
+	// Status label, provided by servers with custom status names
+	StatusText string `json:"Status Text,omitempty"`
+}
+
+// statusName returns status label provided by server, or name of status code
+func (obj objectStatusResponse) statusName() string {
+	if text := strings.TrimSpace(obj.StatusText); text != "" {
+		return text
+	}
+	return objectStatusName(obj.Status)
+}
+
+// statusColor returns color of object status. Status label provided by server
+// is mapped by name, so custom statuses don't depend on status code.
+func (obj objectStatusResponse) statusColor(overrides map[string]string) (string, bool) {
+	if strings.TrimSpace(obj.StatusText) == "" {
+		return objectStatusColor(obj.Status, overrides)
+	}
+	return statusColorByName(obj.statusName(), overrides)
+}
+
+// objectStatusNames contains NetXMS object status names indexed by status code
+var objectStatusNames = []string{
+	"Normal",
+	"Warning",
+	"Minor",
+	"Major",
+	"Critical",
+	"Unknown",
+	"Unmanaged",
+	"Disabled",
+	"Testing",
+}
+
+// objectStatusColors contains default colors indexed by status code
+var objectStatusColors = []string{
+	"rgb(0, 137, 0)",     // Normal
+	"rgb(0, 142, 145)",   // Warning
+	"rgb(201, 198, 0)",   // Minor
+	"rgb(223, 102, 0)",   // Major
+	"rgb(160, 0, 0)",     // Critical
+	"rgb(33, 33, 248)",   // Unknown
+	"rgb(113, 113, 113)", // Unmanaged
+	"rgb(100, 41, 0)",    // Disabled
+	"rgb(138, 0, 143)",   // Testing
+}
+
+// hiddenStatusColor can be configured as status color to exclude objects with that status
+const hiddenStatusColor = "hidden"
+
+// objectStatusColor returns color for given status code, applying configured
+// overrides by status name. Second return value is false if status is hidden.
+func objectStatusColor(status int32, overrides map[string]string) (string, bool) {
+	statusColor := "rgb(128, 128, 128)"
+	if status >= 0 && int(status) < len(objectStatusColors) {
+		statusColor = objectStatusColors[status]
+		if override, ok := overrides[objectStatusNames[status]]; ok {
+			statusColor = override
+		}
+	}
+	return statusColor, statusColor != hiddenStatusColor
+}
+
+// statusColorByName returns color for given status name, applying configured
+// overrides. Names other than standard status names get default color unless
+// configured. Second return value is false if status is hidden.
+func statusColorByName(name string, overrides map[string]string) (string, bool) {
+	statusColor := "rgb(128, 128, 128)"
+	if code := objectStatusCode(name); code != nil && int(*code) < len(objectStatusColors) {
+		statusColor = objectStatusColors[*code]
+	}
+	if override, ok := overrides[name]; ok {
+		statusColor = override
+	}
+	return statusColor, statusColor != hiddenStatusColor
+}
+
+// objectStatusCode returns status code for given status name, nil if name is unknown
+func objectStatusCode(name string) *int32 {
+	for i, statusName := range objectStatusNames {
+		if statusName == name {
+			code := int32(i)
+			return &code
+		}
+	}
+	return nil
+}
+
+// objectStatusName returns status name for given status code
+func objectStatusName(status int32) string {
+	if status >= 0 && int(status) < len(objectStatusNames) {
+		return objectStatusNames[status]
+	}
+	return "Unknown"
+}
+
+// maxChildDepth limits depth of object subtree returned by object children query
+const maxChildDepth = 10
+
+type objectChildResponse struct {
+	Id       int64                 `json:"id"`
+	Name     string                `json:"name"`
+	Class    string                `json:"class"`
+	Status   int32                 `json:"status"`
+	Children []objectChildResponse `json:"children"`
+}
+
+// handleObjectChildrenQuery returns direct children or subtree of the object as table
+func (d *NetXMSDatasource) handleObjectChildrenQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		var qm queryModel
+		if err := json.Unmarshal(q.JSON, &qm); err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			return d.objectChildrenQuery(ctx, req.PluginContext, qm)
+		})
+	}
+
+	return response, nil
+}
+
+func (d *NetXMSDatasource) objectChildrenQuery(ctx context.Context, pCtx backend.PluginContext, qm queryModel) backend.DataResponse {
+	if qm.SourceObjectId == "" {
+		return backend.ErrDataResponse(backend.StatusBadRequest, "sourceObjectId is required")
+	}
+	if _, err := strconv.ParseInt(string(qm.SourceObjectId), 10, 64); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, "sourceObjectId must be numeric")
+	}
+	depth := qm.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+	if depth > maxChildDepth {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("depth must not exceed %d", maxChildDepth))
+	}
+
+	config, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+	}
+	config = config.ForQueryType("objectChildren")
+
+	childrenURL := joinURL(config.ServerAddress, fmt.Sprintf("/v1/grafana/objects/%s/children?depth=%d", qm.SourceObjectId, depth))
+	request, err := newAuthenticatedRequest(ctx, http.MethodGet, childrenURL, nil, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
+	}
+
+	result, err := d.httpClient(ctx, config).Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
+	}
+	defer result.Body.Close()
+
+	body, err := readResponseBody(result.Body, config)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
+	}
+	if isEmptyResponse(qm.AllowEmptyResponse, result.StatusCode, body, config) {
+		parentId, _ := strconv.ParseInt(string(qm.SourceObjectId), 10, 64)
+		return backend.DataResponse{
+			Frames: data.Frames{buildObjectChildrenFrame(nil, parentId, depth)},
+		}
+	}
+	if res, failed := handleUpstreamError(result.StatusCode, body, config); failed {
+		return res
+	}
+
+	if qm.RawJSON {
+		return backend.DataResponse{
+			Frames: data.Frames{rawJSONFrame("object-children", body)},
+		}
+	}
+
+	var children []objectChildResponse
+	if err := json.Unmarshal(body, &children); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
+	}
+
+	parentId, _ := strconv.ParseInt(string(qm.SourceObjectId), 10, 64)
+	return backend.DataResponse{
+		Frames: data.Frames{buildObjectChildrenFrame(children, parentId, depth)},
+	}
+}
+
+// buildObjectChildrenFrame flattens object subtree into table, skipping objects deeper than maxDepth
+func buildObjectChildrenFrame(children []objectChildResponse, parentId int64, maxDepth int) *data.Frame {
+	var ids, parentIds []int64
+	var names, classes, statuses []string
+	var depths []int32
+
+	var walk func(objects []objectChildResponse, parent int64, depth int)
+	walk = func(objects []objectChildResponse, parent int64, depth int) {
+		if depth > maxDepth {
+			return
+		}
+		for _, obj := range objects {
+			ids = append(ids, obj.Id)
+			parentIds = append(parentIds, parent)
+			names = append(names, obj.Name)
+			classes = append(classes, obj.Class)
+			statuses = append(statuses, objectStatusName(obj.Status))
+			depths = append(depths, int32(depth))
+			walk(obj.Children, obj.Id, depth+1)
+		}
+	}
+	walk(children, parentId, 1)
+
+	return data.NewFrame("object-children",
+		data.NewField("Id", nil, ids),
+		data.NewField("Name", nil, names),
+		data.NewField("Class", nil, classes),
+		data.NewField("Status", nil, statuses),
+		data.NewField("Parent Id", nil, parentIds),
+		data.NewField("Depth", nil, depths),
+	)
+}
+
+// buildStatusSummaryFrame counts objects per status, skipping hidden statuses
+// and statuses without objects. Status names are colored as in object frames.
+func buildStatusSummaryFrame(objects []objectStatusResponse, overrides map[string]string) *data.Frame {
+	counts := make(map[string]int64)
+	for _, obj := range objects {
+		counts[obj.statusName()]++
+	}
+	// Standard statuses are ordered by code, custom statuses follow by name
+	statusNames := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		codeA, codeB := objectStatusCode(a), objectStatusCode(b)
+		switch {
+		case codeA != nil && codeB != nil:
+			return cmp.Compare(*codeA, *codeB)
+		case codeA != nil:
+			return -1
+		case codeB != nil:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	names := []string{}
+	values := []int64{}
+	mapper := data.ValueMapper{}
+	for _, name := range statusNames {
+		statusColor, visible := statusColorByName(name, overrides)
+		if !visible {
+			continue
+		}
+		names = append(names, name)
+		values = append(values, counts[name])
+		mapper[name] = data.ValueMappingResult{Text: name, Color: statusColor}
+	}
+
+	statusField := data.NewField("Status", nil, names)
+	statusField.Config = &data.FieldConfig{Mappings: data.ValueMappings{mapper}}
+	return data.NewFrame("status-summary", statusField, data.NewField("Count", nil, values))
+}
+
+// filterObjectsByStatus keeps objects with status listed in filter, or not listed if exclude is set
+func filterObjectsByStatus(objects []objectStatusResponse, filter []string, exclude bool) []objectStatusResponse {
+	listed := make(map[string]bool, len(filter))
+	for _, status := range filter {
+		listed[strings.ToLower(status)] = true
+	}
+
+	filtered := make([]objectStatusResponse, 0, len(objects))
+	for _, obj := range objects {
+		if listed[strings.ToLower(obj.statusName())] != exclude {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+func (d *NetXMSDatasource) handleObjectStatusQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		var qm queryModel
+		if err := json.Unmarshal(q.JSON, &qm); err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			return d.objectStatusQuery(ctx, req.PluginContext, qm)
+		})
+	}
+
+	return response, nil
+}
+
+// objectStatusQuery requests status of objects in scope and converts it into frame per object
+func (d *NetXMSDatasource) objectStatusQuery(ctx context.Context, pCtx backend.PluginContext, qm queryModel) backend.DataResponse {
+	pluginConfig, err := models.LoadPluginSettings(*pCtx.DataSourceInstanceSettings)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+	}
+	pluginConfig = pluginConfig.ForQueryType("objectStatus")
+
+	client := d.httpClient(ctx, pluginConfig)
+
+	url := joinURL(pluginConfig.ServerAddress, "/v1/grafana/objects-status")
+
+	reqBody := map[string]any{}
+	if qm.SourceObjectId != "" {
+		rootObjectIdNum, parseErr := strconv.ParseInt(string(qm.SourceObjectId), 10, 64)
+		if parseErr != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, "sourceObjectId must be numeric")
+		}
+		reqBody["rootObjectId"] = rootObjectIdNum
+	}
+	// Without depth server aggregates status of the whole subtree
+	if qm.Depth > 0 {
+		reqBody["depth"] = qm.Depth
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err))
+	}
+
+	request, err := newAuthenticatedRequest(ctx, http.MethodPost, url, bodyBytes, pluginConfig)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
+	}
+
+	result, err := client.Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
+	}
+
+	body, err := readResponseBody(result.Body, pluginConfig)
+	result.Body.Close()
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
+	}
+
+	if isEmptyResponse(qm.AllowEmptyResponse, result.StatusCode, body, pluginConfig) {
+		return backend.DataResponse{
+			Frames: data.Frames{},
+		}
+	}
+
+	if res, failed := handleUpstreamError(result.StatusCode, body, pluginConfig); failed {
+		return res
+	}
+
+	if qm.RawJSON {
+		return backend.DataResponse{
+			Frames: data.Frames{rawJSONFrame("objects-status", body)},
+		}
+	}
+
+	var statusData []objectStatusResponse
+	if err := json.Unmarshal(body, &statusData); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
+	}
+
+	if len(qm.StatusFilter) > 0 {
+		statusData = filterObjectsByStatus(statusData, qm.StatusFilter, qm.StatusFilterMode == "exclude")
+	}
+
+	if qm.StatusSummary {
+		return backend.DataResponse{
+			Frames: data.Frames{buildStatusSummaryFrame(statusData, pluginConfig.StatusColors)},
+		}
+	}
+
+	// Summary counts all objects in scope, including those dropped by frame limit
+	var summary *data.Frame
+	if qm.WithStatusSummary {
+		summary = buildStatusSummaryFrame(statusData, pluginConfig.StatusColors)
+	}
+
+	var notice *data.Notice
+	if qm.MaxFrames > 0 {
+		statusData, notice = limitStatusObjects(statusData, qm.MaxFrames, pluginConfig.StatusColors)
+	}
+
+	frames := make(data.Frames, 0, len(statusData))
+	for _, obj := range statusData {
+		statusColor, visible := obj.statusColor(pluginConfig.StatusColors)
+		if !visible {
+			continue
+		}
+		frame := data.NewFrame(obj.Name)
+
+		// Use DisplayName to show object name in stat panel
+		nameField := data.NewField("Name", nil, []string{obj.Name})
+		nameField.Config = &data.FieldConfig{
+			Mappings: data.ValueMappings{
+				data.ValueMapper{
+					obj.Name: {Text: obj.Name, Color: statusColor},
+				},
+			},
+		}
+		if obj.Id != 0 {
+			nameField.Config.Links = objectLinks(pluginConfig.WebUIAddress, strconv.FormatInt(obj.Id, 10))
+		}
+		frame.Fields = append(frame.Fields, nameField)
+		if obj.Availability != nil {
+			availabilityField := data.NewField("Availability", nil, []float64{*obj.Availability})
+			availabilityField.Config = (&data.FieldConfig{Unit: "percent"}).SetMin(0).SetMax(100)
+			frame.Fields = append(frame.Fields, availabilityField)
+		}
+		frames = append(frames, frame)
+	}
+
+	if len(frames) == 0 {
+		// Single empty frame tells panel why there is nothing to show
+		frame := data.NewFrame("objects-status")
+		frame.AppendNotices(data.Notice{Severity: data.NoticeSeverityInfo, Text: "no objects in scope"})
+		frames = append(frames, frame)
+	}
+	if notice != nil {
+		frames[0].AppendNotices(*notice)
+	}
+	if summary != nil {
+		frames = append(frames, summary)
+	}
+	return backend.DataResponse{
+		Frames: frames,
+	}
+}
+
+// statusRanks orders object statuses from worst to best for truncated object status results
+var statusRanks = map[int32]int{
+	4: 5, // Critical
+	3: 4, // Major
+	2: 3, // Minor
+	1: 2, // Warning
+	5: 1, // Unknown
+}
+
+// limitStatusObjects keeps at most limit visible objects, preferring objects
+// with worst status. Notice is returned if objects were dropped.
+func limitStatusObjects(objects []objectStatusResponse, limit int, overrides map[string]string) ([]objectStatusResponse, *data.Notice) {
+	visible := make([]objectStatusResponse, 0, len(objects))
+	for _, obj := range objects {
+		if _, ok := obj.statusColor(overrides); ok {
+			visible = append(visible, obj)
+		}
+	}
+	if len(visible) <= limit {
+		return visible, nil
+	}
+
+	slices.SortStableFunc(visible, func(a, b objectStatusResponse) int {
+		return statusRanks[b.Status] - statusRanks[a.Status]
+	})
+	return visible[:limit], &data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("Showing %d of %d objects with worst status", limit, len(visible)),
+	}
+}
+
+// objectLinks creates drill-down link to object page in NetXMS web UI, nil if web UI address is not configured
+func objectLinks(webUIAddress, objectId string) []data.DataLink {
+	if webUIAddress == "" {
+		return nil
+	}
+	return []data.DataLink{{
+		Title:       "Open in NetXMS",
+		URL:         strings.TrimSuffix(webUIAddress, "/") + "/#object=" + objectId,
+		TargetBlank: true,
+	}}
+}
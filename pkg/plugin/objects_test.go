@@ -0,0 +1,569 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func TestObjectListWithoutSorting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"objects":[{"id":2,"name":"b"},{"id":1,"name":"a"}]}`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "sortObjects": false}`),
+	}
+	response := callTestResource(t, settings, "alarmObjects")
+
+	var result testObjectList
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 2 || result.Objects[0].Name != "b" || result.Objects[1].Name != "a" {
+		t.Errorf("Expected server order to be preserved, got: %s", string(response.Body))
+	}
+
+	response = callTestResource(t, newTestSettings(server.URL), "alarmObjects")
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Objects[0].Name != "a" {
+		t.Errorf("Expected sorted objects, got: %s", string(response.Body))
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"Node2", "Node10", true},
+		{"Node10", "Node2", false},
+		{"Node02", "Node10", true},
+		{"Node", "Node1", true},
+		{"Alpha", "Beta", true},
+		{"Node1a", "Node1b", true},
+		{"Node5", "Node5", false},
+	}
+
+	for _, tt := range tests {
+		if result := naturalLess(tt.a, tt.b); result != tt.expected {
+			t.Errorf("naturalLess(%q, %q) = %v, expected %v", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestObjectListNaturalSort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"objects":[{"id":1,"name":"Node10"},{"id":2,"name":"Node2"},{"id":3,"name":"Node1"}]}`))
+	}))
+	defer server.Close()
+
+	response := callTestResource(t, newTestSettings(server.URL), "dciObjects")
+	var result testObjectList
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 3 || result.Objects[0].Name != "Node1" || result.Objects[1].Name != "Node2" || result.Objects[2].Name != "Node10" {
+		t.Errorf("Expected natural order, got: %s", string(response.Body))
+	}
+}
+
+func TestInterfacesResource(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"objects":[` +
+			`{"id":11,"name":"eth1","ifIndex":3,"adminState":"Up","operState":"Down"},` +
+			`{"id":10,"name":"eth0","ifIndex":2,"adminState":"Up","operState":"Up"}]}`))
+	}))
+	defer server.Close()
+
+	response := callTestResource(t, newTestSettings(server.URL), "interfaces?objectId=5")
+	if response.Status != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d", response.Status)
+	}
+	if requestedPath != "/v1/grafana/objects/5/interface-list" {
+		t.Errorf("Unexpected upstream path: %s", requestedPath)
+	}
+
+	var result struct {
+		Objects []struct {
+			Id         int64  `json:"id"`
+			Name       string `json:"name"`
+			IfIndex    int    `json:"ifIndex"`
+			AdminState string `json:"adminState"`
+			OperState  string `json:"operState"`
+		} `json:"objects"`
+	}
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 2 {
+		t.Fatalf("Expected 2 interfaces, got: %d", len(result.Objects))
+	}
+	first := result.Objects[0]
+	if first.Name != "eth0" || first.IfIndex != 2 || first.AdminState != "Up" || first.OperState != "Up" {
+		t.Errorf("Unexpected interface: %+v", first)
+	}
+
+	response = callTestResource(t, newTestSettings(server.URL), "interfaces")
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for missing objectId, got: %d", response.Status)
+	}
+}
+
+func TestObjectStatusColorRemap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1","Status":6},{"Name":"node2","Status":4},{"Name":"node3","Status":7}]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "statusColors": {"Unmanaged": "transparent", "Disabled": "hidden"}}`),
+	}
+	response := runTestQuery(t, settings, "objectStatus", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 2 {
+		t.Fatalf("Expected hidden status to be excluded, got %d frames", len(response.Frames))
+	}
+
+	colors := map[string]string{}
+	for _, frame := range response.Frames {
+		mapper, ok := frame.Fields[0].Config.Mappings[0].(data.ValueMapper)
+		if !ok {
+			t.Fatal("Expected value mapper")
+		}
+		colors[frame.Name] = mapper[frame.Name].Color
+	}
+	if colors["node1"] != "transparent" {
+		t.Errorf("Expected remapped color for Unmanaged, got: %q", colors["node1"])
+	}
+	if colors["node2"] != "rgb(160, 0, 0)" {
+		t.Errorf("Expected default color for Critical, got: %q", colors["node2"])
+	}
+}
+
+func TestObjectStatusFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1","Status":0},{"Name":"node2","Status":4},{"Name":"node3","Status":0}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1", "statusFilter": ["Normal"], "statusFilterMode": "exclude"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 1 || response.Frames[0].Name != "node2" {
+		t.Errorf("Expected only non-normal node2, got %d frames", len(response.Frames))
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1", "statusFilter": ["normal"]}`)
+	if len(response.Frames) != 2 {
+		t.Errorf("Expected 2 normal objects, got %d frames", len(response.Frames))
+	}
+}
+
+func TestDrillDownLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "alarms") {
+			_, _ = w.Write([]byte(`[{"Id":1,"Source":"node1","Source Id":100}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"Id":100,"Name":"node1","Status":0}]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "webUiAddress": "https://netxms.local/nxmc/"}`),
+	}
+
+	response := runTestQuery(t, settings, "alarms", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	sourceField, _ := response.Frames[0].FieldByName("Source")
+	if sourceField == nil || sourceField.Config == nil || len(sourceField.Config.Links) != 1 {
+		t.Fatal("Expected drill-down link on alarm source field")
+	}
+	if url := sourceField.Config.Links[0].URL; url != `https://netxms.local/nxmc/#object=${__data.fields["Source Id"]}` {
+		t.Errorf("Unexpected alarm source link: %s", url)
+	}
+
+	response = runTestQuery(t, settings, "objectStatus", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	links := response.Frames[0].Fields[0].Config.Links
+	if len(links) != 1 || links[0].URL != "https://netxms.local/nxmc/#object=100" {
+		t.Errorf("Unexpected object link: %v", links)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1"}`)
+	if links := response.Frames[0].Fields[0].Config.Links; links != nil {
+		t.Errorf("Expected no links without web UI address, got: %v", links)
+	}
+}
+
+func TestObjectStatusAvailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1","Status":0,"Availability":99.5},{"Name":"node2","Status":0}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	field, _ := response.Frames[0].FieldByName("Availability")
+	if field == nil {
+		t.Fatal("Expected availability field")
+	}
+	if availability, _ := field.At(0).(float64); availability != 99.5 {
+		t.Errorf("Expected availability 99.5, got: %v", availability)
+	}
+	if field.Config.Unit != "percent" {
+		t.Errorf("Expected percent unit, got: %q", field.Config.Unit)
+	}
+	if len(response.Frames[1].Fields) != 1 {
+		t.Errorf("Expected no availability field when server omits it, got: %d fields", len(response.Frames[1].Fields))
+	}
+}
+
+func TestObjectChildrenQuery(t *testing.T) {
+	var requestURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURL = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"id":10,"name":"Rack 1","class":"Rack","status":0,"children":[` +
+			`{"id":100,"name":"node1","class":"Node","status":4,"children":[` +
+			`{"id":1000,"name":"eth0","class":"Interface","status":0}]}]},` +
+			`{"id":11,"name":"Rack 2","class":"Rack","status":1}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectChildren", `{"sourceObjectId": "2", "depth": 2}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if requestURL != "/v1/grafana/objects/2/children?depth=2" {
+		t.Errorf("Unexpected upstream request: %s", requestURL)
+	}
+
+	frame := response.Frames[0]
+	if frame.Rows() != 3 {
+		t.Fatalf("Expected 3 objects within depth 2, got: %d", frame.Rows())
+	}
+	nameField, _ := frame.FieldByName("Name")
+	parentField, _ := frame.FieldByName("Parent Id")
+	statusField, _ := frame.FieldByName("Status")
+	if name, _ := nameField.At(1).(string); name != "node1" {
+		t.Errorf("Expected subtree in depth-first order, got: %q", name)
+	}
+	if parent, _ := parentField.At(1).(int64); parent != 10 {
+		t.Errorf("Expected node1 parent 10, got: %d", parent)
+	}
+	if parent, _ := parentField.At(0).(int64); parent != 2 {
+		t.Errorf("Expected direct child parent to be root object, got: %d", parent)
+	}
+	if status, _ := statusField.At(1).(string); status != "Critical" {
+		t.Errorf("Expected status name Critical, got: %q", status)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectChildren", `{"sourceObjectId": "2", "depth": 50}`)
+	if response.Error == nil {
+		t.Error("Expected error for depth above limit")
+	}
+}
+
+func TestObjectStatusDepth(t *testing.T) {
+	var requestBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestBody = nil
+		_ = json.NewDecoder(r.Body).Decode(&requestBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1", "depth": 2}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if depth, _ := requestBody["depth"].(float64); depth != 2 {
+		t.Errorf("Expected depth 2 in request body, got: %v", requestBody["depth"])
+	}
+
+	runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1"}`)
+	if _, ok := requestBody["depth"]; ok {
+		t.Error("Expected no depth in request body when not set")
+	}
+}
+
+func TestObjectStatusSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1","Status":0},{"Name":"node2","Status":4},{"Name":"node3","Status":0},` +
+			`{"Name":"node4","Status":7},{"Name":"node5","Status":0}]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "statusColors": {"Disabled": "hidden"}}`),
+	}
+	response := runTestQuery(t, settings, "objectStatus", `{"sourceObjectId": "1", "statusSummary": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 1 {
+		t.Fatalf("Expected single summary frame, got: %d", len(response.Frames))
+	}
+	frame := response.Frames[0]
+	counts := map[string]int64{}
+	for i := range frame.Rows() {
+		status, _ := frame.Fields[0].At(i).(string)
+		count, _ := frame.Fields[1].At(i).(int64)
+		counts[status] = count
+	}
+	if len(counts) != 2 || counts["Normal"] != 3 || counts["Critical"] != 1 {
+		t.Errorf("Expected Normal: 3 and Critical: 1, got: %v", counts)
+	}
+	mapper, _ := frame.Fields[0].Config.Mappings[0].(data.ValueMapper)
+	if mapper["Critical"].Color != "rgb(160, 0, 0)" {
+		t.Errorf("Expected default status color, got: %q", mapper["Critical"].Color)
+	}
+}
+
+func TestResolveObjectResource(t *testing.T) {
+	var filter string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"objects":[{"id":10,"name":"router"},{"id":11,"name":"switch"},{"id":12,"name":"router"},{"id":13,"name":"server"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		expected []int64
+	}{
+		{"switch", []int64{11}},
+		{"router", []int64{10, 12}},
+		{"printer", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := callTestResource(t, newTestSettings(server.URL), "resolveObject?filter=dci&name="+tt.name)
+			if response.Status != http.StatusOK {
+				t.Fatalf("Expected status 200, got: %d (%s)", response.Status, response.Body)
+			}
+			if filter != "dci" {
+				t.Errorf("Expected object list filtered for DCIs, got filter: %q", filter)
+			}
+			var result struct {
+				Objects []objectRef `json:"objects"`
+			}
+			if err := json.Unmarshal(response.Body, &result); err != nil {
+				t.Fatal(err)
+			}
+			ids := []int64{}
+			for _, obj := range result.Objects {
+				ids = append(ids, obj.Id)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected ids %v, got: %v", tt.expected, ids)
+			}
+		})
+	}
+
+	for _, path := range []string{"resolveObject?name=router", "resolveObject?name=router&filter=all"} {
+		if response := callTestResource(t, newTestSettings(server.URL), path); response.Status != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got: %d", path, response.Status)
+		}
+	}
+}
+
+func TestObjectStatusMaxFrames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1","Status":0},{"Name":"node2","Status":2},{"Name":"node3","Status":0},` +
+			`{"Name":"node4","Status":4},{"Name":"node5","Status":1}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1", "maxFrames": 3}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	var names []string
+	for _, frame := range response.Frames {
+		names = append(names, frame.Name)
+	}
+	if fmt.Sprint(names) != "[node4 node2 node5]" {
+		t.Errorf("Expected worst status objects first, got: %v", names)
+	}
+	if len(response.Frames[0].Meta.Notices) != 1 || !strings.Contains(response.Frames[0].Meta.Notices[0].Text, "3 of 5") {
+		t.Errorf("Expected truncation notice, got: %+v", response.Frames[0].Meta)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1", "maxFrames": 5}`)
+	if len(response.Frames) != 5 || response.Frames[0].Meta != nil {
+		t.Error("Expected all objects in server order without notice when under limit")
+	}
+}
+
+func TestObjectListWithPaths(t *testing.T) {
+	var includePath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		includePath = r.URL.Query().Get("includePath")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"objects":[` +
+			`{"id":1,"name":"switch","path":["Site B","Rack 1"]},` +
+			`{"id":2,"name":"switch","path":"/Site A/Rack 2/"},` +
+			`{"id":3,"name":"Entire Network"}]}`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "showObjectPaths": true}`),
+	}
+	response := callTestResource(t, settings, "dciObjects")
+	if includePath != "true" {
+		t.Errorf("Expected object paths to be requested, got includePath=%q", includePath)
+	}
+
+	var result testObjectList
+	if err := json.Unmarshal(response.Body, &result); err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(result.Objects))
+	for i, object := range result.Objects {
+		names[i] = object.Name
+	}
+	expected := []string{"Entire Network", "Site A/Rack 2/switch", "Site B/Rack 1/switch"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("Expected labels with paths %v, got: %v", expected, names)
+	}
+
+	callTestResource(t, newTestSettings(server.URL), "dciObjects")
+	if includePath != "" {
+		t.Errorf("Expected object paths not to be requested by default, got includePath=%q", includePath)
+	}
+}
+
+func TestObjectStatusEmptyScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 1 {
+		t.Fatalf("Expected single empty frame, got: %d frames", len(response.Frames))
+	}
+	frame := response.Frames[0]
+	if len(frame.Fields) != 0 {
+		t.Errorf("Expected frame without fields, got: %d fields", len(frame.Fields))
+	}
+	if frame.Meta == nil || len(frame.Meta.Notices) != 1 || frame.Meta.Notices[0].Text != "no objects in scope" {
+		t.Errorf("Expected no objects in scope notice, got: %+v", frame.Meta)
+	}
+}
+
+func TestObjectStatusTextMapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Id":1,"Name":"node1","Status":12,"Status Text":"Maintenance"},` +
+			`{"Id":2,"Name":"node2","Status":4},` +
+			`{"Id":3,"Name":"node3","Status":3,"Status Text":"Degraded"},` +
+			`{"Id":4,"Name":"node4","Status":1,"Status Text":"Warning"}]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "statusColors": {"Maintenance": "purple"}}`),
+	}
+	response := runTestQuery(t, settings, "objectStatus", `{"sourceObjectId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	expected := map[string]string{
+		"node1": "purple",              // custom status colored by configured text mapping
+		"node2": objectStatusColors[4], // no text, colored by status code
+		"node3": "rgb(128, 128, 128)",  // custom status without mapping gets default color
+		"node4": objectStatusColors[1], // standard status name
+	}
+	for _, frame := range response.Frames {
+		color := frame.Fields[0].Config.Mappings[0].(data.ValueMapper)[frame.Name].Color
+		if color != expected[frame.Name] {
+			t.Errorf("Expected color %q for %s, got: %q", expected[frame.Name], frame.Name, color)
+		}
+	}
+
+	response = runTestQuery(t, settings, "objectStatus", `{"sourceObjectId": "1", "statusSummary": true}`)
+	statuses := response.Frames[0].Fields[0]
+	var names []string
+	for i := range statuses.Len() {
+		names = append(names, statuses.At(i).(string))
+	}
+	if !slices.Equal(names, []string{"Warning", "Critical", "Degraded", "Maintenance"}) {
+		t.Errorf("Expected summary by status text, got: %v", names)
+	}
+}
+
+func TestObjectStatusWithSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1","Status":0},{"Name":"node2","Status":4},{"Name":"node3","Status":0},{"Name":"node4","Status":2}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectStatus", `{"sourceObjectId": "1", "withStatusSummary": true, "maxFrames": 2}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 3 {
+		t.Fatalf("Expected 2 object frames and summary frame, got: %d frames", len(response.Frames))
+	}
+	if response.Frames[0].Name != "node2" || response.Frames[1].Name != "node4" {
+		t.Errorf("Expected object frames first, got: %s, %s", response.Frames[0].Name, response.Frames[1].Name)
+	}
+
+	summary := response.Frames[2]
+	if summary.Name != "status-summary" {
+		t.Fatalf("Expected status-summary frame last, got: %s", summary.Name)
+	}
+	counts := map[string]int64{}
+	for i := range summary.Rows() {
+		counts[summary.Fields[0].At(i).(string)] = summary.Fields[1].At(i).(int64)
+	}
+	expected := map[string]int64{"Normal": 2, "Minor": 1, "Critical": 1}
+	if !maps.Equal(counts, expected) {
+		t.Errorf("Expected summary of all objects %v, got: %v", expected, counts)
+	}
+}
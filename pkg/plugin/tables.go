@@ -0,0 +1,1004 @@
+package plugin
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+type requiredField struct {
+	field   string
+	message string
+}
+
+type tableQueryConfig struct {
+	queryType   string
+	url         string
+	frameName   string
+	required    []requiredField
+	formatBody  func(map[string]any) (map[string]any, error)
+	expandField string // field which may contain list of values, each producing separate frame
+	cacheable   bool   // responses may be cached when cache TTL is configured
+	detectTime  bool   // timestamp column is converted into time field for time series visualization
+	objectLinks bool   // object id column is linked to object page in NetXMS web UI
+}
+
+// buildResponse converts table query response body into frames according to query configuration
+func (c tableQueryConfig) buildResponse(qm map[string]any, body []byte, frameName string, webUIAddress string) backend.DataResponse {
+	if rawJSON, _ := qm["rawJson"].(bool); rawJSON {
+		return buildTableResponse(qm, body, frameName)
+	}
+
+	body, envelope := splitTableEnvelope(body)
+	res := buildTableResponse(qm, body, frameName)
+	if res.Error != nil {
+		return res
+	}
+	for _, frame := range res.Frames {
+		applyColumnUnits(frame, envelope.Units)
+		applyColumnFormats(frame, envelope.Formats)
+		if envelope.Total != nil {
+			setTotalCount(frame, *envelope.Total)
+		}
+	}
+	if c.detectTime {
+		timeColumn, _ := qm["timeColumn"].(string)
+		for _, frame := range res.Frames {
+			if err := convertTimeColumn(frame, timeColumn); err != nil {
+				return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+			}
+		}
+	}
+	if stringBooleans, _ := qm["stringBooleans"].(bool); stringBooleans {
+		for _, frame := range res.Frames {
+			convertBooleanStrings(frame)
+		}
+	}
+	if emptyAsNull, _ := qm["emptyAsNull"].(bool); emptyAsNull {
+		for _, frame := range res.Frames {
+			nullEmptyStrings(frame)
+		}
+	}
+	if transpose, _ := qm["transpose"].(bool); transpose {
+		for i, frame := range res.Frames {
+			res.Frames[i] = transposeSingleRow(frame)
+		}
+		return res
+	}
+	if c.objectLinks && webUIAddress != "" {
+		idColumn, _ := qm["objectIdColumn"].(string)
+		for _, frame := range res.Frames {
+			addObjectRowLinks(frame, idColumn, webUIAddress)
+		}
+	}
+	return res
+}
+
+// transposeSingleRow converts frame with single row into "key" and "value"
+// columns with one row per original column. Frames with other number of rows
+// are returned unchanged.
+func transposeSingleRow(frame *data.Frame) *data.Frame {
+	if len(frame.Fields) == 0 || frame.Fields[0].Len() != 1 {
+		return frame
+	}
+	keys := make([]string, len(frame.Fields))
+	values := make([]string, len(frame.Fields))
+	for i, field := range frame.Fields {
+		keys[i] = field.Name
+		value, _ := field.ConcreteAt(0)
+		switch v := value.(type) {
+		case time.Time:
+			values[i] = v.Format(time.RFC3339)
+		case bool:
+			values[i] = strconv.FormatBool(v)
+		default:
+			values[i] = formatTableValue(v)
+		}
+	}
+	transposed := data.NewFrame(frame.Name,
+		data.NewField("key", nil, keys),
+		data.NewField("value", nil, values),
+	)
+	transposed.Meta = frame.Meta
+	return transposed
+}
+
+// objectIdColumns are column names detected as object id when id column is not set in query
+var objectIdColumns = []string{"id", "object id", "objectid"}
+
+// addObjectRowLinks links object id column, and name column if present, to
+// object page of each row. Id column is detected by name if not given.
+func addObjectRowLinks(frame *data.Frame, idColumn string, webUIAddress string) {
+	var idField *data.Field
+	for _, field := range frame.Fields {
+		if idColumn == "" && slices.Contains(objectIdColumns, strings.ToLower(field.Name)) || field.Name == idColumn {
+			idField = field
+			break
+		}
+	}
+	if idField == nil {
+		return
+	}
+
+	links := objectLinks(webUIAddress, fmt.Sprintf(`${__data.fields[%q]}`, idField.Name))
+	for _, field := range frame.Fields {
+		if field != idField && !strings.EqualFold(field.Name, "name") {
+			continue
+		}
+		if field.Config == nil {
+			field.Config = &data.FieldConfig{}
+		}
+		field.Config.Links = links
+	}
+}
+
+// convertBooleanStrings replaces string fields where all non-empty values are
+// "true" or "false" with nullable boolean fields colored by value
+func convertBooleanStrings(frame *data.Frame) {
+	for i, field := range frame.Fields {
+		if field.Type() != data.FieldTypeString {
+			continue
+		}
+		values := make([]*bool, field.Len())
+		isBoolean, hasValues := true, false
+		for j := range values {
+			str, _ := field.At(j).(string)
+			if str == "" {
+				continue
+			}
+			lower := strings.ToLower(str)
+			if lower != "true" && lower != "false" {
+				isBoolean = false
+				break
+			}
+			value := lower == "true"
+			values[j] = &value
+			hasValues = true
+		}
+		if !isBoolean || !hasValues {
+			continue
+		}
+		boolField := data.NewField(field.Name, field.Labels, values)
+		boolField.Config = field.Config
+		if boolField.Config == nil {
+			boolField.Config = &data.FieldConfig{}
+		}
+		boolField.Config.Mappings = data.ValueMappings{
+			data.ValueMapper{
+				"true":  {Text: "true", Color: "green"},
+				"false": {Text: "false", Color: "red"},
+			},
+		}
+		frame.Fields[i] = boolField
+	}
+}
+
+// nullEmptyStrings replaces string fields with nullable ones where empty strings are nulls
+func nullEmptyStrings(frame *data.Frame) {
+	for i, field := range frame.Fields {
+		if field.Type() != data.FieldTypeString {
+			continue
+		}
+		values := make([]*string, field.Len())
+		for j := range values {
+			if str, _ := field.At(j).(string); str != "" {
+				values[j] = &str
+			}
+		}
+		nullable := data.NewField(field.Name, field.Labels, values)
+		nullable.Config = field.Config
+		frame.Fields[i] = nullable
+	}
+}
+
+// tableEnvelope holds metadata sent with table rows by newer servers
+type tableEnvelope struct {
+	Rows    json.RawMessage         `json:"rows"`
+	Units   map[string]string       `json:"units"`
+	Formats map[string]columnFormat `json:"formats"`
+	Total   *int64                  `json:"total"`
+}
+
+// columnFormat is formatting hint of table column provided by server
+type columnFormat struct {
+	Unit        string            `json:"unit"`
+	Decimals    *uint16           `json:"decimals"`
+	ColorScheme string            `json:"colorScheme"` // Grafana color mode, e.g. "thresholds" or "continuous-GrYlRd"
+	Thresholds  []columnThreshold `json:"thresholds"`
+}
+
+// columnThreshold is threshold step of table column, step without value is base color
+type columnThreshold struct {
+	Value *float64 `json:"value"`
+	Color string   `json:"color"`
+}
+
+// defaultBaseThresholdColor is used when server provides thresholds without base step
+const defaultBaseThresholdColor = "green"
+
+// splitTableEnvelope unwraps table response sent as {"rows": [...], "units": {"column": "unit"}, "formats": {...}, "total": N}
+// by servers providing column units or paginating results. Other responses are returned unchanged without metadata.
+func splitTableEnvelope(body []byte) ([]byte, tableEnvelope) {
+	if !isJSONObject(body) {
+		return body, tableEnvelope{}
+	}
+	var envelope tableEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Rows == nil {
+		return body, tableEnvelope{}
+	}
+	return envelope.Rows, envelope
+}
+
+// applyColumnFormats maps column formatting hints provided by server into
+// field unit, decimals, thresholds and color scheme. Fields without hints are
+// left unchanged.
+func applyColumnFormats(frame *data.Frame, formats map[string]columnFormat) {
+	for _, field := range frame.Fields {
+		format, ok := formats[field.Name]
+		if !ok {
+			continue
+		}
+		if field.Config == nil {
+			field.Config = &data.FieldConfig{}
+		}
+		if format.Unit != "" {
+			field.Config.Unit = format.Unit
+		}
+		if format.Decimals != nil {
+			field.Config.SetDecimals(*format.Decimals)
+		}
+		if len(format.Thresholds) > 0 {
+			field.Config.Thresholds = buildThresholds(format.Thresholds)
+		}
+		switch {
+		case format.ColorScheme != "":
+			field.Config.Color = map[string]any{"mode": format.ColorScheme}
+		case len(format.Thresholds) > 0:
+			field.Config.Color = map[string]any{"mode": "thresholds"}
+		}
+	}
+}
+
+// buildThresholds converts server threshold steps into absolute thresholds
+// sorted by value, with base step first as required by Grafana
+func buildThresholds(steps []columnThreshold) *data.ThresholdsConfig {
+	base := data.Threshold{Value: data.ConfFloat64(math.Inf(-1)), Color: defaultBaseThresholdColor}
+	thresholds := []data.Threshold{}
+	for _, step := range steps {
+		if step.Value == nil {
+			base.Color = step.Color
+			continue
+		}
+		thresholds = append(thresholds, data.Threshold{Value: data.ConfFloat64(*step.Value), Color: step.Color})
+	}
+	slices.SortStableFunc(thresholds, func(a, b data.Threshold) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return &data.ThresholdsConfig{
+		Mode:  data.ThresholdsModeAbsolute,
+		Steps: append([]data.Threshold{base}, thresholds...),
+	}
+}
+
+// applyColumnUnits sets unit of frame fields which have unit provided by server
+func applyColumnUnits(frame *data.Frame, units map[string]string) {
+	for _, field := range frame.Fields {
+		unit := units[field.Name]
+		if unit == "" {
+			continue
+		}
+		if field.Config == nil {
+			field.Config = &data.FieldConfig{}
+		}
+		field.Config.Unit = unit
+	}
+}
+
+func (d *NetXMSDatasource) handleTableQuery(ctx context.Context, req *backend.QueryDataRequest, queryConfig tableQueryConfig) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		var qm map[string]any
+		if err := json.Unmarshal(q.JSON, &qm); err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("json unmarshal: %v", err.Error()))
+			continue
+		}
+		normalizeQueryIDs(qm, "sourceObjectId", "objectQueryId", "summaryTableId")
+
+		valid := true
+		for _, req := range queryConfig.required {
+			if !hasQueryValue(qm[req.field]) {
+				response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, req.message)
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		pluginConfig, err := models.LoadPluginSettings(*req.PluginContext.DataSourceInstanceSettings)
+		if err != nil {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load plugin settings: %v", err))
+			continue
+		}
+		pluginConfig = pluginConfig.ForQueryType(queryConfig.queryType)
+
+		subQueries := []map[string]any{qm}
+		if queryConfig.expandField != "" {
+			subQueries = expandQueryList(qm, queryConfig.expandField)
+		}
+
+		baseFrameName := queryConfig.frameName
+		if name, ok := qm["frameName"].(string); ok && name != "" {
+			baseFrameName = name
+		}
+
+		response.Responses[q.RefID] = d.timeQuery(func() backend.DataResponse {
+			var res backend.DataResponse
+			for _, subQuery := range subQueries {
+				frameName := baseFrameName
+				if len(subQueries) > 1 {
+					frameName = fmt.Sprintf("%s-%v", baseFrameName, subQuery[queryConfig.expandField])
+				}
+				subRes := d.tableQuery(ctx, pluginConfig, subQuery, queryConfig, frameName)
+				if subRes.Error != nil {
+					return subRes
+				}
+				res.Frames = append(res.Frames, subRes.Frames...)
+			}
+			return res
+		})
+	}
+
+	return response, nil
+}
+
+func (d *NetXMSDatasource) tableQuery(ctx context.Context, pluginConfig *models.PluginSettings, qm map[string]any, queryConfig tableQueryConfig, frameName string) backend.DataResponse {
+	client := d.httpClient(ctx, pluginConfig)
+
+	url := joinURL(pluginConfig.ServerAddress, queryConfig.url)
+
+	reqBody, err := queryConfig.formatBody(qm)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to format request body: %v", err))
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to marshal request body: %v", err))
+	}
+
+	var cacheKey string
+	cacheTTL := pluginConfig.ObjectQueryCacheTTL()
+	if queryConfig.cacheable && cacheTTL > 0 {
+		cacheKey = responseCacheKey(url, bodyBytes)
+		if body, ok := d.cache.get(cacheKey); ok {
+			return queryConfig.buildResponse(qm, body, frameName, pluginConfig.WebUIAddress)
+		}
+	}
+
+	request, err := newAuthenticatedRequest(ctx, http.MethodPost, url, bodyBytes, pluginConfig)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to create request: %v", err))
+	}
+
+	result, err := client.Do(request)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to connect to server: %v", err))
+	}
+
+	body, err := readResponseBody(result.Body, pluginConfig)
+	result.Body.Close()
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to read response: %v", err))
+	}
+
+	if allowEmpty, _ := qm["allowEmptyResponse"].(bool); isEmptyResponse(allowEmpty, result.StatusCode, body, pluginConfig) {
+		return backend.DataResponse{
+			Frames: data.Frames{data.NewFrame(frameName)},
+		}
+	}
+
+	if res, failed := handleUpstreamError(result.StatusCode, body, pluginConfig); failed {
+		return res
+	}
+
+	if cacheKey != "" {
+		d.cache.set(cacheKey, body, cacheTTL)
+	}
+
+	return queryConfig.buildResponse(qm, body, frameName, pluginConfig.WebUIAddress)
+}
+
+// buildTableResponse converts JSON array of row objects into frame, keeping column order of the first row
+//
+//nolint:gocyclo // dynamic column types require multiple conversion paths
+func buildTableResponse(qm map[string]any, body []byte, frameName string) backend.DataResponse {
+	if rawJSON, _ := qm["rawJson"].(bool); rawJSON {
+		return backend.DataResponse{
+			Frames: data.Frames{rawJSONFrame(frameName, body)},
+		}
+	}
+
+	if flatten, _ := qm["flattenGroups"].(bool); flatten {
+		flattened, err := flattenNestedColumns(body)
+		if err != nil {
+			return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to flatten grouped columns: %v", err))
+		}
+		body = flattened
+	}
+
+	var rows []json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse response: %v", err))
+	}
+	if len(rows) > 0 && !isJSONObject(rows[0]) {
+		return backend.DataResponse{
+			Frames: data.Frames{buildValueColumnFrame(frameName, rows)},
+		}
+	}
+
+	frame := data.NewFrame(frameName)
+
+	if len(rows) > 0 {
+		// Rows are decoded preserving key order, so that duplicate keys can be
+		// kept as separate columns instead of last value overriding others
+		tableResponse := make([]map[string]any, len(rows))
+		var columnOrder []string
+		for i, row := range rows {
+			keys, values, err := decodeJSONObject(row)
+			if err != nil {
+				return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse row %d: %v", i+1, err))
+			}
+			if i == 0 {
+				columnOrder = keys
+			}
+			tableResponse[i] = values
+		}
+
+		var nulledColumns []string
+		for _, columnName := range columnOrder {
+			field, nulled := buildTableField(columnName, tableResponse)
+			frame.Fields = append(frame.Fields, field)
+			if nulled {
+				nulledColumns = append(nulledColumns, columnName)
+			}
+		}
+		if len(nulledColumns) > 0 {
+			frame.AppendNotices(data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     fmt.Sprintf("NaN or infinity values in numeric columns are shown as null: %s", strings.Join(nulledColumns, ", ")),
+			})
+		}
+	}
+
+	return backend.DataResponse{
+		Frames: data.Frames{frame},
+	}
+}
+
+// buildTableField creates field for table column. Column is numeric or boolean
+// only if all non-null values are of that type (numeric strings are accepted in
+// numeric columns), such columns are nullable. Mixed columns are converted to
+// strings entirely, with null converted to empty string. Returns true if
+// string values of numeric column were replaced with nulls.
+func buildTableField(columnName string, rows []map[string]any) (*data.Field, bool) {
+	switch tableColumnType(columnName, rows) {
+	case data.FieldTypeNullableFloat64:
+		values := make([]*float64, len(rows))
+		nulled := false
+		for i, row := range rows {
+			switch v := row[columnName].(type) {
+			case float64:
+				values[i] = &v
+			case string:
+				if f, err := strconv.ParseFloat(v, 64); err == nil && isFinite(f) {
+					values[i] = &f
+				} else {
+					nulled = true
+				}
+			}
+		}
+		return data.NewField(columnName, nil, values), nulled
+	case data.FieldTypeNullableBool:
+		values := make([]*bool, len(rows))
+		for i, row := range rows {
+			if v, ok := row[columnName].(bool); ok {
+				values[i] = &v
+			}
+		}
+		return data.NewField(columnName, nil, values), false
+	default:
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = formatTableValue(row[columnName])
+		}
+		return data.NewField(columnName, nil, values), false
+	}
+}
+
+// tableColumnType classifies all values of column before choosing field type,
+// so that result doesn't depend on row order and value of unexpected type
+// can't break typed field
+func tableColumnType(columnName string, rows []map[string]any) data.FieldType {
+	var numbers, numericStrings, booleans, texts bool
+	for _, row := range rows {
+		switch v := row[columnName].(type) {
+		case nil:
+		case float64:
+			numbers = true
+		case bool:
+			booleans = true
+		case string:
+			// Numeric strings don't decide column type, but are allowed in numeric
+			// columns. NaN and infinity strings are treated as nulls.
+			if f, err := strconv.ParseFloat(v, 64); err != nil {
+				texts = true
+			} else if isFinite(f) {
+				numericStrings = true
+			}
+		default:
+			return data.FieldTypeString
+		}
+	}
+
+	switch {
+	case texts || booleans && (numbers || numericStrings):
+		return data.FieldTypeString
+	case booleans:
+		return data.FieldTypeNullableBool
+	case numbers:
+		return data.FieldTypeNullableFloat64
+	case numericStrings:
+		return data.FieldTypeString
+	}
+	return data.FieldTypeUnknown
+}
+
+// isFinite checks that number is neither NaN nor infinity
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// formatTableValue converts table cell value to string, formatting numbers without exponent
+func formatTableValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// convertTimeColumn replaces string column containing timestamps with time field.
+// Column can be given explicitly, otherwise first string column where all values
+// are timestamps is used. Frame is left unchanged if no such column is found.
+func convertTimeColumn(frame *data.Frame, columnName string) error {
+	for i, field := range frame.Fields {
+		if columnName != "" && field.Name != columnName {
+			continue
+		}
+		timeField, err := parseTimeField(field)
+		if err != nil {
+			if columnName != "" {
+				return fmt.Errorf("time column %q: %w", columnName, err)
+			}
+			continue
+		}
+		frame.Fields[i] = timeField
+		return nil
+	}
+	if columnName != "" {
+		return fmt.Errorf("time column %q not found", columnName)
+	}
+	return nil
+}
+
+// parseTimeField converts string field with timestamp values into time field
+func parseTimeField(field *data.Field) (*data.Field, error) {
+	if field.Type() != data.FieldTypeString || field.Len() == 0 {
+		return nil, errors.New("column does not contain timestamps")
+	}
+	values := make([]time.Time, field.Len())
+	for i := range values {
+		str, _ := field.At(i).(string)
+		parsed, ok := parseTimestamp(str)
+		if !ok {
+			return nil, fmt.Errorf("unsupported timestamp %q", str)
+		}
+		values[i] = parsed
+	}
+	return data.NewField(field.Name, field.Labels, values), nil
+}
+
+// parseTimestamp parses timestamp string in one of supported alarm time formats
+func parseTimestamp(str string) (time.Time, bool) {
+	for _, layout := range alarmTimeLayouts {
+		if parsed, err := time.Parse(layout, str); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isJSONObject checks if raw JSON value is an object
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// buildValueColumnFrame creates frame with single "value" column for array of
+// non-object elements. Strings are kept as is, other values are shown as JSON.
+func buildValueColumnFrame(frameName string, rows []json.RawMessage) *data.Frame {
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		var str string
+		if err := json.Unmarshal(row, &str); err == nil {
+			values[i] = str
+			continue
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, row); err == nil {
+			values[i] = compacted.String()
+		} else {
+			values[i] = string(row)
+		}
+	}
+	return data.NewFrame(frameName, data.NewField("value", nil, values))
+}
+
+// flattenNestedColumns rewrites array of row objects so that nested objects
+// (grouped columns) become top level columns named "Group/Column". Column order is preserved.
+func flattenNestedColumns(body []byte) ([]byte, error) {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("unmarshal rows: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		first := true
+		if err := flattenObject(&buf, row, "", &first); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// flattenObject writes members of JSON object to buf, recursing into nested objects with key prefix
+func flattenObject(buf *bytes.Buffer, raw json.RawMessage, prefix string, first *bool) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", token)
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("read key token: %w", err)
+		}
+		keyStr, ok := key.(string)
+		if !ok {
+			return fmt.Errorf("expected string key, got %v", key)
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("decode value for key %q: %w", keyStr, err)
+		}
+
+		name := prefix + keyStr
+		trimmed := bytes.TrimSpace(value)
+		if len(trimmed) > 0 && trimmed[0] == '{' {
+			if err := flattenObject(buf, trimmed, name+"/", first); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+		encodedName, err := json.Marshal(name)
+		if err != nil {
+			return fmt.Errorf("encode column name: %w", err)
+		}
+		buf.Write(encodedName)
+		buf.WriteByte(':')
+		buf.Write(trimmed)
+	}
+	return nil
+}
+
+// hasQueryValue checks if query model field is a non-empty string or a non-empty list
+func hasQueryValue(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// normalizeQueryIDs converts ids encoded in query JSON as numbers into strings
+// and ids given in exponent notation into plain decimal form
+func normalizeQueryIDs(qm map[string]any, fields ...string) {
+	for _, field := range fields {
+		switch v := qm[field].(type) {
+		case float64:
+			qm[field] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			if id, err := parseID(v); err == nil {
+				qm[field] = strconv.FormatInt(id, 10)
+			}
+		}
+	}
+}
+
+// expandQueryList splits query model with list value in given field into
+// separate query models with single value each
+func expandQueryList(qm map[string]any, field string) []map[string]any {
+	list, ok := qm[field].([]any)
+	if !ok {
+		return []map[string]any{qm}
+	}
+
+	result := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		subQuery := make(map[string]any, len(qm))
+		for k, v := range qm {
+			subQuery[k] = v
+		}
+		switch v := item.(type) {
+		case string:
+			subQuery[field] = v
+		case float64:
+			subQuery[field] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			subQuery[field] = fmt.Sprintf("%v", v)
+		}
+		result = append(result, subQuery)
+	}
+	return result
+}
+
+// Default and maximum number of rows returned by table preview
+const (
+	defaultPreviewRows = 10
+	maxPreviewRows     = 100
+)
+
+type tablePreviewRequest struct {
+	QueryType string          `json:"queryType"`
+	Query     json.RawMessage `json:"query"`
+	Limit     int             `json:"limit"`
+}
+
+type tablePreview struct {
+	Name      string   `json:"name"`
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	TotalRows int      `json:"totalRows"`
+}
+
+// handlePreviewTable runs summary table or object query and returns first rows of
+// each result frame, so query editor can show result shape
+func (ds *NetXMSDatasource) handlePreviewTable(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeJSONError(rw, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var preview tablePreviewRequest
+	if err := json.NewDecoder(req.Body).Decode(&preview); err != nil {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if preview.QueryType != "summaryTables" && preview.QueryType != "objectQueries" {
+		writeJSONError(rw, http.StatusBadRequest, fmt.Sprintf("unsupported queryType: %s", preview.QueryType))
+		return
+	}
+	limit := preview.Limit
+	if limit <= 0 {
+		limit = defaultPreviewRows
+	}
+	limit = min(limit, maxPreviewRows)
+
+	pCtx := backend.PluginConfigFromContext(req.Context())
+	queryResp, err := ds.queryHandler.QueryData(req.Context(), &backend.QueryDataRequest{
+		PluginContext: pCtx,
+		Queries: []backend.DataQuery{
+			{RefID: "preview", QueryType: preview.QueryType, JSON: preview.Query},
+		},
+	})
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+	res := queryResp.Responses["preview"]
+	if res.Error != nil {
+		writeJSONError(rw, http.StatusBadRequest, res.Error.Error())
+		return
+	}
+
+	previews := make([]tablePreview, 0, len(res.Frames))
+	for _, frame := range res.Frames {
+		columns := make([]string, len(frame.Fields))
+		for i, field := range frame.Fields {
+			columns[i] = field.Name
+		}
+		rowCount := min(frame.Rows(), limit)
+		rows := make([][]any, rowCount)
+		for i := range rowCount {
+			rows[i] = frame.RowCopy(i)
+		}
+		previews = append(previews, tablePreview{
+			Name:      frame.Name,
+			Columns:   columns,
+			Rows:      rows,
+			TotalRows: frame.Rows(),
+		})
+	}
+
+	body, err := json.Marshal(previews)
+	if err != nil {
+		writeJSONError(rw, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	writeJSONResponse(rw, body)
+}
+
+func (d *NetXMSDatasource) handleSummaryTableQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return d.handleTableQuery(ctx, req, tableQueryConfig{
+		queryType:   "summaryTables",
+		url:         "/v1/grafana/infinity/summary-table",
+		frameName:   "summary-table",
+		expandField: "summaryTableId",
+		required: []requiredField{
+			{"summaryTableId", "tableId is required"},
+		},
+		formatBody: func(qm map[string]any) (map[string]any, error) {
+			reqBody := make(map[string]any)
+
+			if rootObjectId, ok := qm["sourceObjectId"].(string); ok && rootObjectId != "" {
+				rootObjectIdNum, err := strconv.ParseInt(rootObjectId, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid rootObjectId: %w", err)
+				}
+				reqBody["rootObjectId"] = rootObjectIdNum
+			}
+
+			if tableId, ok := qm["summaryTableId"].(string); ok && tableId != "" {
+				tableIdNum, err := strconv.ParseInt(tableId, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid tableId: %w", err)
+				}
+				reqBody["tableId"] = tableIdNum
+			}
+
+			return reqBody, nil
+		},
+	})
+}
+
+func (d *NetXMSDatasource) handleObjectQueryQuery(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return d.handleTableQuery(ctx, req, tableQueryConfig{
+		queryType:   "objectQueries",
+		url:         "/v1/grafana/infinity/object-query",
+		frameName:   "object-query",
+		cacheable:   true,
+		detectTime:  true,
+		objectLinks: true,
+		required: []requiredField{
+			{"objectQueryId", "queryId is required"},
+		},
+		formatBody: func(qm map[string]any) (map[string]any, error) {
+			reqBody := make(map[string]any)
+
+			if rootObjectId, ok := qm["sourceObjectId"].(string); ok && rootObjectId != "" {
+				rootObjectIdNum, err := strconv.ParseInt(rootObjectId, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid rootObjectId: %w", err)
+				}
+				reqBody["rootObjectId"] = rootObjectIdNum
+			}
+
+			if queryId, ok := qm["objectQueryId"].(string); ok && queryId != "" {
+				queryIdNum, err := strconv.ParseInt(queryId, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid queryId: %w", err)
+				}
+				reqBody["queryId"] = queryIdNum
+			}
+
+			if values, ok := qm["queryParameters"].(string); ok && values != "" {
+				var parsedValues []map[string]any
+				if err := json.Unmarshal([]byte(values), &parsedValues); err != nil {
+					return nil, fmt.Errorf("invalid queryParameters JSON: %w", err)
+				}
+				reqBody["values"] = parsedValues
+			}
+
+			return reqBody, nil
+		},
+	})
+}
+
+// decodeJSONObject decodes JSON object preserving key order. Duplicate keys
+// are kept as separate entries by adding numeric suffix ("Name", "Name (2)").
+// Null is decoded as object without keys.
+func decodeJSONObject(rawData []byte) ([]string, map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(rawData))
+	token, err := dec.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read opening token: %w", err)
+	}
+	if token == nil {
+		return nil, map[string]any{}, nil
+	}
+	if token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected object, got %v", token)
+	}
+
+	var keys []string
+	values := make(map[string]any)
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, nil, fmt.Errorf("read key token: %w", err)
+		}
+		keyStr, ok := key.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("expected string key, got %v", key)
+		}
+
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, fmt.Errorf("decode value for key %q: %w", keyStr, err)
+		}
+
+		uniqueKey := keyStr
+		for n := 2; ; n++ {
+			if _, exists := values[uniqueKey]; !exists {
+				break
+			}
+			uniqueKey = fmt.Sprintf("%s (%d)", keyStr, n)
+		}
+		keys = append(keys, uniqueKey)
+		values[uniqueKey] = value
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("read closing token: %w", err)
+	}
+
+	return keys, values, nil
+}
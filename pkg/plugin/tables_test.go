@@ -0,0 +1,669 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func TestMultipleSummaryTables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{{"Node": "node1", "Table": fmt.Sprintf("%v", body["tableId"])}})
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "summaryTables", `{"summaryTableId": ["1", "2"]}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 2 {
+		t.Fatalf("Expected 2 frames, got: %d", len(response.Frames))
+	}
+	if response.Frames[0].Name != "summary-table-1" || response.Frames[1].Name != "summary-table-2" {
+		t.Errorf("Expected distinct frame names, got: %s, %s", response.Frames[0].Name, response.Frames[1].Name)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "summaryTables", `{"summaryTableId": "3"}`)
+	if len(response.Frames) != 1 || response.Frames[0].Name != "summary-table" {
+		t.Error("Expected single frame named summary-table")
+	}
+}
+
+func TestTableQueryCustomFrameName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "frameName": "interfaces"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if len(response.Frames) != 1 || response.Frames[0].Name != "interfaces" {
+		t.Errorf("Expected frame named interfaces, got: %s", response.Frames[0].Name)
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Frames[0].Name != "object-query" {
+		t.Errorf("Expected default frame name object-query, got: %s", response.Frames[0].Name)
+	}
+}
+
+func TestObjectQueryCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1"}]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "objectQueryCacheTTL": 60}`),
+	}
+	ds := newTestDatasource(t, settings)
+	query := `{"objectQueryId": "1", "sourceObjectId": "2", "queryParameters": "[{\"name\":\"a\",\"value\":\"1\"}]"}`
+
+	for range 2 {
+		response := runDatasourceQuery(t, ds, settings, "objectQueries", query)
+		if response.Error != nil {
+			t.Fatalf("Expected no error, got: %v", response.Error)
+		}
+		if response.Frames[0].Rows() != 1 {
+			t.Errorf("Expected 1 row, got: %d", response.Frames[0].Rows())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected cache hit for identical parameters, got %d upstream calls", calls)
+	}
+
+	runDatasourceQuery(t, ds, settings, "objectQueries", `{"objectQueryId": "1", "sourceObjectId": "3"}`)
+	if calls != 2 {
+		t.Errorf("Expected cache miss for different root object, got %d upstream calls", calls)
+	}
+
+	noCacheSettings := newTestSettings(server.URL)
+	noCacheDs := newTestDatasource(t, noCacheSettings)
+	runDatasourceQuery(t, noCacheDs, noCacheSettings, "objectQueries", query)
+	runDatasourceQuery(t, noCacheDs, noCacheSettings, "objectQueries", query)
+	if calls != 4 {
+		t.Errorf("Expected no caching without TTL, got %d upstream calls", calls)
+	}
+}
+
+func TestSummaryTableFlattenGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Node":"node1","CPU":{"Usage":"10","Load":"0.5"},"Memory":{"Used":"1024"}},` +
+			`{"Node":"node2","CPU":{"Usage":"20","Load":"1.5"},"Memory":{"Used":"2048"}}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "summaryTables", `{"summaryTableId": "1", "flattenGroups": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	expected := []string{"Node", "CPU/Usage", "CPU/Load", "Memory/Used"}
+	if len(frame.Fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got: %d", len(expected), len(frame.Fields))
+	}
+	for i, name := range expected {
+		if frame.Fields[i].Name != name {
+			t.Errorf("Expected field %d to be %q, got: %q", i, name, frame.Fields[i].Name)
+		}
+	}
+	if value, _ := frame.Fields[3].At(1).(string); value != "2048" {
+		t.Errorf("Expected flattened value 2048, got: %q", value)
+	}
+}
+
+func TestTableQueryScalarArray(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["node1", 42, true, [1, 2]]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if len(frame.Fields) != 1 || frame.Fields[0].Name != "value" {
+		t.Fatalf("Expected single value column, got: %d fields", len(frame.Fields))
+	}
+	for i, expected := range []string{"node1", "42", "true", "[1,2]"} {
+		if value, _ := frame.Fields[0].At(i).(string); value != expected {
+			t.Errorf("Expected row %d to be %q, got: %q", i, expected, value)
+		}
+	}
+}
+
+func TestBuildTableResponseTypedColumns(t *testing.T) {
+	body := []byte(`[` +
+		`{"Name":"node1","Load":null,"Up":true,"Tags":["a"]},` +
+		`{"Name":null,"Load":1.5,"Up":null,"Tags":null},` +
+		`{"Name":"node3","Load":"2.5","Up":false,"Tags":["b"]}]`)
+
+	response := buildTableResponse(map[string]any{}, body, "table")
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if len(frame.Fields) != 4 {
+		t.Fatalf("Expected 4 fields, got: %d", len(frame.Fields))
+	}
+
+	if name, _ := frame.Fields[0].At(1).(string); name != "" {
+		t.Errorf("Expected null string to become empty, got: %q", name)
+	}
+	if load, _ := frame.Fields[1].At(0).(*float64); load != nil {
+		t.Errorf("Expected leading null in numeric column, got: %v", *load)
+	}
+	if load, _ := frame.Fields[1].At(1).(*float64); load == nil || *load != 1.5 {
+		t.Errorf("Expected numeric column typed from first non-null value, got: %v", load)
+	}
+	if load, _ := frame.Fields[1].At(2).(*float64); load == nil || *load != 2.5 {
+		t.Errorf("Expected numeric string to be parsed, got: %v", load)
+	}
+	if up, _ := frame.Fields[2].At(2).(*bool); up == nil || *up {
+		t.Errorf("Expected false boolean, got: %v", up)
+	}
+	if tags, _ := frame.Fields[3].At(0).(string); tags != "[a]" {
+		t.Errorf("Expected array value as string, got: %q", tags)
+	}
+}
+
+func TestBuildTableResponseNonNumericStrings(t *testing.T) {
+	// Non-numeric strings turn column into string column instead of nulls
+	response := buildTableResponse(map[string]any{}, []byte(`[{"Load":1.5},{"Load":"n/a"}]`), "table")
+	if field := response.Frames[0].Fields[0]; field.Type() != data.FieldTypeString || field.At(1).(string) != "n/a" {
+		t.Errorf("Expected string column keeping non-numeric value, got: %v", field.Type())
+	}
+	if meta := response.Frames[0].Meta; meta != nil && len(meta.Notices) > 0 {
+		t.Errorf("Expected no notices, got: %v", meta.Notices)
+	}
+
+	// Non-finite values can't be stored in numeric field, they are nulled with warning
+	response = buildTableResponse(map[string]any{}, []byte(`[{"Load":1.5},{"Load":"Infinity"}]`), "table")
+	frame := response.Frames[0]
+	if load, _ := frame.Fields[0].At(1).(*float64); frame.Fields[0].Type() != data.FieldTypeNullableFloat64 || load != nil {
+		t.Errorf("Expected null in numeric column, got: %v", load)
+	}
+	if frame.Meta == nil || len(frame.Meta.Notices) != 1 || !strings.Contains(frame.Meta.Notices[0].Text, "Load") {
+		t.Errorf("Expected warning naming nulled column, got: %v", frame.Meta)
+	}
+}
+
+func BenchmarkBuildTableResponse(b *testing.B) {
+	rows := make([]string, 0, 1000)
+	for i := range 1000 {
+		rows = append(rows, fmt.Sprintf(`{"Name":"node%d","Load":%d.5,"Up":true}`, i, i))
+	}
+	body := []byte(`[` + strings.Join(rows, ",") + `]`)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		buildTableResponse(map[string]any{}, body, "table")
+	}
+}
+
+func TestBuildTableResponseDuplicateKeys(t *testing.T) {
+	body := []byte(`[{"Name":"eth0","Speed":"1G","Name":"uplink"},{"Name":"eth1","Speed":"10G","Name":"backup"}]`)
+
+	response := buildTableResponse(map[string]any{}, body, "table")
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	names := make([]string, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		names = append(names, field.Name)
+	}
+	if strings.Join(names, ",") != "Name,Speed,Name (2)" {
+		t.Fatalf("Expected de-duplicated columns, got: %v", names)
+	}
+	if value, _ := frame.Fields[0].At(1).(string); value != "eth1" {
+		t.Errorf("Expected first Name value eth1, got: %q", value)
+	}
+	if value, _ := frame.Fields[2].At(1).(string); value != "backup" {
+		t.Errorf("Expected duplicate Name value backup, got: %q", value)
+	}
+}
+
+func TestObjectQueryTimeColumn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"Node":"node1","Last Poll":"2026-01-01T00:00:00Z","Changed":"2026-01-01 10:00:00"},` +
+			`{"Node":"node2","Last Poll":"2026-01-01T00:05:00Z","Changed":"2026-01-01 11:00:00"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	fields := response.Frames[0].Fields
+	if fields[1].Type() != data.FieldTypeTime {
+		t.Fatalf("Expected detected time column, got: %v", fields[1].Type())
+	}
+	if ts, _ := fields[1].At(1).(time.Time); !ts.Equal(time.Date(2026, 1, 1, 0, 5, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timestamp: %v", ts)
+	}
+	if fields[0].Type() != data.FieldTypeString || fields[2].Type() != data.FieldTypeString {
+		t.Error("Expected only first timestamp column to be converted")
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "timeColumn": "Changed"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	fields = response.Frames[0].Fields
+	if fields[2].Type() != data.FieldTypeTime || fields[1].Type() != data.FieldTypeString {
+		t.Error("Expected only configured time column to be converted")
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "timeColumn": "Node"}`)
+	if response.Error == nil {
+		t.Error("Expected error for time column without timestamps")
+	}
+}
+
+func TestObjectQueryColumnUnits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[{"Node":"node1","CPU":12.5,"Memory":1024}],"units":{"CPU":"percent","Memory":"bytes"}}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	units := map[string]string{}
+	for _, field := range response.Frames[0].Fields {
+		if field.Config != nil {
+			units[field.Name] = field.Config.Unit
+		}
+	}
+	if units["CPU"] != "percent" || units["Memory"] != "bytes" {
+		t.Errorf("Expected server provided units, got: %v", units)
+	}
+	if _, ok := units["Node"]; ok {
+		t.Errorf("Expected column without unit to stay unitless, got: %q", units["Node"])
+	}
+	if response.Frames[0].Rows() != 1 {
+		t.Errorf("Expected rows from envelope, got: %d", response.Frames[0].Rows())
+	}
+}
+
+func TestPreviewTableResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rows := make([]string, 0, 25)
+		for i := range 25 {
+			rows = append(rows, fmt.Sprintf(`{"Name":"node%d","Status":"Normal"}`, i+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` + strings.Join(rows, ",") + `]`))
+	}))
+	defer server.Close()
+
+	response := callTestResourceAs(t, newTestSettings(server.URL), nil, http.MethodPost, "previewTable",
+		[]byte(`{"queryType": "objectQueries", "query": {"objectQueryId": "1"}, "limit": 5}`))
+	if response.Status != http.StatusOK {
+		t.Fatalf("Expected status 200, got: %d (%s)", response.Status, response.Body)
+	}
+
+	var previews []struct {
+		Columns   []string `json:"columns"`
+		Rows      [][]any  `json:"rows"`
+		TotalRows int      `json:"totalRows"`
+	}
+	if err := json.Unmarshal(response.Body, &previews); err != nil {
+		t.Fatal(err)
+	}
+	if len(previews) != 1 {
+		t.Fatalf("Expected one frame preview, got: %d", len(previews))
+	}
+	if len(previews[0].Rows) != 5 || previews[0].TotalRows != 25 {
+		t.Errorf("Expected 5 of 25 rows, got: %d of %d", len(previews[0].Rows), previews[0].TotalRows)
+	}
+	if strings.Join(previews[0].Columns, ",") != "Name,Status" || previews[0].Rows[0][0] != "node1" {
+		t.Errorf("Unexpected preview content: %v %v", previews[0].Columns, previews[0].Rows[0])
+	}
+
+	response = callTestResourceAs(t, newTestSettings(server.URL), nil, http.MethodPost, "previewTable",
+		[]byte(`{"queryType": "alarms", "query": {}}`))
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for non-table query type, got: %d", response.Status)
+	}
+}
+
+func TestTableQueryTotalRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[{"Node":"node1"},{"Node":"node2"}],"total":120}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "summaryTables", `{"sourceObjectId": "1", "summaryTableId": "2"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 2 {
+		t.Fatalf("Expected 2 rows, got: %d", frame.Rows())
+	}
+	if frame.Meta == nil || len(frame.Meta.Stats) != 1 || frame.Meta.Stats[0].Value != 120 {
+		t.Errorf("Expected total 120 in frame metadata, got: %+v", frame.Meta)
+	}
+}
+
+func TestTableQueryEmptyAsNull(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Node":"node1","Comment":""},{"Node":"node2","Comment":"backup"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if field, _ := response.Frames[0].FieldByName("Comment"); field.Type() != data.FieldTypeString {
+		t.Errorf("Expected plain string field by default, got: %v", field.Type())
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "emptyAsNull": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	field, _ := response.Frames[0].FieldByName("Comment")
+	if field.Type() != data.FieldTypeNullableString {
+		t.Fatalf("Expected nullable string field, got: %v", field.Type())
+	}
+	if value, _ := field.At(0).(*string); value != nil {
+		t.Errorf("Expected empty string to become null, got: %q", *value)
+	}
+	if value, _ := field.At(1).(*string); value == nil || *value != "backup" {
+		t.Errorf("Expected non-empty value to be kept, got: %v", value)
+	}
+}
+
+func TestObjectQueryRowLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Node":10,"Name":"node1","Load":1.5},{"Node":11,"Name":"node2","Load":2.5}]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "webUiAddress": "https://netxms.local/nxmc/"}`),
+	}
+	response := runTestQuery(t, settings, "objectQueries", `{"objectQueryId": "1", "objectIdColumn": "Node"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	for _, name := range []string{"Node", "Name"} {
+		field, _ := frame.FieldByName(name)
+		if field.Config == nil || len(field.Config.Links) != 1 {
+			t.Fatalf("Expected link on %s column", name)
+		}
+		if url := field.Config.Links[0].URL; url != `https://netxms.local/nxmc/#object=${__data.fields["Node"]}` {
+			t.Errorf("Unexpected link URL on %s column: %s", name, url)
+		}
+	}
+	if field, _ := frame.FieldByName("Load"); field.Config != nil && len(field.Config.Links) > 0 {
+		t.Error("Expected no link on other columns")
+	}
+
+	// Without configured column object id is detected by name
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"Id":10,"Load":1.5}]`))
+	})
+	response = runTestQuery(t, settings, "objectQueries", `{"objectQueryId": "2"}`)
+	if field, _ := response.Frames[0].FieldByName("Id"); field.Config == nil || len(field.Config.Links) != 1 {
+		t.Error("Expected link on detected id column")
+	}
+}
+
+func TestBuildTableResponseMixedColumn(t *testing.T) {
+	body := []byte(`[{"Port":22,"Up":true},{"Port":"ssh-alt","Up":"unknown"},{"Port":1e6,"Up":null}]`)
+	response := buildTableResponse(map[string]any{}, body, "table")
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	for _, name := range []string{"Port", "Up"} {
+		if field, _ := frame.FieldByName(name); field.Type() != data.FieldTypeString {
+			t.Errorf("Expected mixed column %s to be string, got: %v", name, field.Type())
+		}
+	}
+	port, _ := frame.FieldByName("Port")
+	for i, expected := range []string{"22", "ssh-alt", "1000000"} {
+		if value, _ := port.At(i).(string); value != expected {
+			t.Errorf("Expected %q at row %d, got: %q", expected, i, value)
+		}
+	}
+}
+
+func TestTableColumnTypeRowOrder(t *testing.T) {
+	tests := []struct {
+		values   []any
+		expected data.FieldType
+	}{
+		{[]any{"12", 13.0}, data.FieldTypeNullableFloat64},
+		{[]any{13.0, "12"}, data.FieldTypeNullableFloat64},
+		{[]any{"12", "13"}, data.FieldTypeString},
+		{[]any{"12", true}, data.FieldTypeString},
+		{[]any{true, "12"}, data.FieldTypeString},
+		{[]any{"NaN", 1.0}, data.FieldTypeNullableFloat64},
+		{[]any{"NaN", nil}, data.FieldTypeUnknown},
+	}
+	for _, tt := range tests {
+		rows := make([]map[string]any, len(tt.values))
+		for i, value := range tt.values {
+			rows[i] = map[string]any{"Value": value}
+		}
+		if columnType := tableColumnType("Value", rows); columnType != tt.expected {
+			t.Errorf("%v: expected %v, got: %v", tt.values, tt.expected, columnType)
+		}
+	}
+}
+
+func TestBuildTableResponseNestedValueInNumericColumn(t *testing.T) {
+	// Nested values are formatted as strings, numeric column must not be built from them
+	body := []byte(`[{"Load":1.5},{"Load":{"avg":2.5}},{"Load":[3]}]`)
+	var response backend.DataResponse
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Expected no panic, got: %v", r)
+			}
+		}()
+		response = buildTableResponse(map[string]any{}, body, "table")
+	}()
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	field := response.Frames[0].Fields[0]
+	if field.Type() != data.FieldTypeString {
+		t.Fatalf("Expected fallback to string field, got: %v", field.Type())
+	}
+	if value, _ := field.At(0).(string); value != "1.5" {
+		t.Errorf("Expected numeric value formatted as string, got: %q", value)
+	}
+}
+
+func TestTableQueryStringBooleans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Node":"node1","Managed":"true","Flag":"yes"},{"Node":"node2","Managed":"False","Flag":"no"},{"Node":"node3","Managed":"","Flag":"yes"}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if field, _ := response.Frames[0].FieldByName("Managed"); field.Type() != data.FieldTypeString {
+		t.Errorf("Expected string field by default, got: %v", field.Type())
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "stringBooleans": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	field, _ := response.Frames[0].FieldByName("Managed")
+	if field.Type() != data.FieldTypeNullableBool {
+		t.Fatalf("Expected boolean field, got: %v", field.Type())
+	}
+	for i, expected := range []*bool{ptrTo(true), ptrTo(false), nil} {
+		value, _ := field.At(i).(*bool)
+		if (value == nil) != (expected == nil) || (value != nil && *value != *expected) {
+			t.Errorf("Unexpected value at row %d: %v", i, value)
+		}
+	}
+	if field.Config == nil || len(field.Config.Mappings) != 1 {
+		t.Error("Expected value mappings on boolean field")
+	}
+	if flag, _ := response.Frames[0].FieldByName("Flag"); flag.Type() != data.FieldTypeString {
+		t.Errorf("Expected non-boolean column to stay string, got: %v", flag.Type())
+	}
+}
+
+func TestTableQueryColumnFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[{"Node":"node1","CPU":42.5,"Memory":70}],"formats":{` +
+			`"CPU":{"unit":"percent","decimals":1,"thresholds":[{"value":90,"color":"red"},{"value":null,"color":"blue"},{"value":75,"color":"orange"}]},` +
+			`"Memory":{"colorScheme":"continuous-GrYlRd"}}}`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "summaryTables", `{"sourceObjectId": "1", "summaryTableId": "2"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+
+	cpu, _ := frame.FieldByName("CPU")
+	if cpu.Config == nil || cpu.Config.Unit != "percent" || cpu.Config.Decimals == nil || *cpu.Config.Decimals != 1 {
+		t.Fatalf("Expected CPU unit and decimals from server, got: %+v", cpu.Config)
+	}
+	thresholds := cpu.Config.Thresholds
+	if thresholds == nil || thresholds.Mode != data.ThresholdsModeAbsolute || len(thresholds.Steps) != 3 {
+		t.Fatalf("Expected 3 absolute threshold steps, got: %+v", thresholds)
+	}
+	expected := []data.Threshold{
+		{Value: data.ConfFloat64(math.Inf(-1)), Color: "blue"},
+		{Value: 75, Color: "orange"},
+		{Value: 90, Color: "red"},
+	}
+	if !slices.Equal(thresholds.Steps, expected) {
+		t.Errorf("Expected sorted steps %+v, got: %+v", expected, thresholds.Steps)
+	}
+	if cpu.Config.Color["mode"] != "thresholds" {
+		t.Errorf("Expected thresholds color mode, got: %v", cpu.Config.Color)
+	}
+
+	memory, _ := frame.FieldByName("Memory")
+	if memory.Config == nil || memory.Config.Color["mode"] != "continuous-GrYlRd" || memory.Config.Thresholds != nil {
+		t.Errorf("Expected color scheme only for Memory, got: %+v", memory.Config)
+	}
+	if node, _ := frame.FieldByName("Node"); node.Config != nil {
+		t.Errorf("Expected plain Node column without formatting, got: %+v", node.Config)
+	}
+}
+
+func TestSummaryTablesByObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Interface table applies only to nodes, object 5 is a container
+		if r.URL.Query().Get("objectId") == "5" {
+			_, _ = w.Write([]byte(`{"objects":[{"id":1,"name":"Availability"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"objects":[{"id":2,"name":"Interfaces"},{"id":1,"name":"Availability"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"summaryTables", []string{"Availability", "Interfaces"}},
+		{"summaryTables?objectId=5", []string{"Availability"}},
+	}
+	for _, tt := range tests {
+		response := callTestResource(t, newTestSettings(server.URL), tt.path)
+		var result testObjectList
+		if err := json.Unmarshal(response.Body, &result); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, object := range result.Objects {
+			names = append(names, object.Name)
+		}
+		if !slices.Equal(names, tt.expected) {
+			t.Errorf("Expected tables %v for %s, got: %v", tt.expected, tt.path, names)
+		}
+	}
+
+	response := callTestResource(t, newTestSettings(server.URL), "summaryTables?objectId=abc")
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("Expected bad request for invalid objectId, got: %d", response.Status)
+	}
+}
+
+func TestTableQueryTranspose(t *testing.T) {
+	body := `[{"Name":"node1","Uptime":3600,"Managed":true}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "transpose": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Name != "object-query" || len(frame.Fields) != 2 || frame.Fields[0].Name != "key" || frame.Fields[1].Name != "value" {
+		t.Fatalf("Expected key/value frame, got: %v", frame)
+	}
+	var got []string
+	for i := range frame.Rows() {
+		got = append(got, frame.Fields[0].At(i).(string)+"="+frame.Fields[1].At(i).(string))
+	}
+	if want := []string{"Name=node1", "Uptime=3600", "Managed=true"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got: %v", want, got)
+	}
+
+	// Multiple rows can't be transposed into key/value pairs
+	body = `[{"Name":"node1"},{"Name":"node2"}]`
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1", "transpose": true}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if frame := response.Frames[0]; len(frame.Fields) != 1 || frame.Fields[0].Name != "Name" || frame.Rows() != 2 {
+		t.Errorf("Expected multi-row result unchanged, got: %v", frame)
+	}
+}
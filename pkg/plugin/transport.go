@@ -0,0 +1,594 @@
+package plugin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+// apiVersionHeader tells server which version of grafana API plugin expects
+const apiVersionHeader = "X-NetXMS-API-Version"
+
+// errServerAddressMissing is reported instead of request creation error when server address is not configured
+var errServerAddressMissing = errors.New("server address is missing")
+
+// newAuthenticatedRequest creates upstream request with authorization and API
+// version headers. JSON content type is set when body is provided.
+func newAuthenticatedRequest(ctx context.Context, method, url string, body []byte, config *models.PluginSettings) (*http.Request, error) {
+	if config.ServerAddress == "" {
+		return nil, errServerAddressMissing
+	}
+	var bodyReader io.Reader = http.NoBody
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.Header.Set("Authorization", "Bearer "+config.Secrets.ApiKey)
+	request.Header.Set(apiVersionHeader, config.GetAPIVersion())
+	return request, nil
+}
+
+// responseCache keeps upstream response bodies for limited time
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]responseCacheEntry
+}
+
+type responseCacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		entries: make(map[string]responseCacheEntry),
+	}
+}
+
+// get returns cached body if it exists and is not expired
+func (c *responseCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// set stores body for given time, removing expired entries
+func (c *responseCache) set(key string, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = responseCacheEntry{
+		body:    body,
+		expires: now.Add(ttl),
+	}
+}
+
+// responseCacheKey builds cache key from request URL and body (query id, root object and parameters)
+func responseCacheKey(url string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(url))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// newHTTPClient creates client for requests to NetXMS server. Configured timeout
+// is shortened to the context deadline so requests respect Grafana query timeout.
+func newHTTPClient(ctx context.Context, config *models.PluginSettings) *http.Client {
+	timeout := config.RequestTimeout()
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > 0 && remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return &http.Client{
+		Timeout:       timeout,
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// maxRedirects limits number of redirects followed for single request
+const maxRedirects = 10
+
+// checkRedirect keeps authorization header on redirects within the same host
+// (e.g. http to https upgrade) and refuses redirects to other hosts, which
+// would otherwise fail with confusing authorization error. Redirects from https
+// to http are refused, so that API key is never sent in cleartext.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	initial := via[0]
+	if req.URL.Hostname() != initial.URL.Hostname() {
+		return fmt.Errorf("server redirected to different host %s, update server address in datasource settings", req.URL.Host)
+	}
+	if slices.ContainsFunc(via, func(r *http.Request) bool { return r.URL.Scheme == "https" }) && req.URL.Scheme != "https" {
+		return fmt.Errorf("server redirected from https to %s, refusing to send API key unencrypted", req.URL.Scheme)
+	}
+	if auth := initial.Header.Get("Authorization"); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return nil
+}
+
+// httpClient creates client for requests to NetXMS server, limiting number of
+// concurrent requests of this datasource instance
+func (d *NetXMSDatasource) httpClient(ctx context.Context, config *models.PluginSettings) *http.Client {
+	client := newHTTPClient(ctx, config)
+	transport := d.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if config.GzipRequests {
+		transport = &gzipTransport{base: transport, threshold: config.GzipRequestThreshold(), accepted: &d.gzipAccepted}
+	}
+	client.Transport = transport
+	if d.requestSlots != nil {
+		client.Transport = &limitedTransport{base: transport, slots: d.requestSlots}
+	}
+	if config.RetryOn429 {
+		// Request slot is not held while waiting for retry
+		client.Transport = &retryAfterTransport{base: client.Transport, deadline: time.Now().Add(client.Timeout)}
+	}
+	return client
+}
+
+// maxRetryAfter limits delay requested by server which is honored
+const maxRetryAfter = time.Minute
+
+// retryAfterTransport retries rate limited request once after delay given by
+// Retry-After header in seconds. Response is returned as is when retry would
+// not complete before deadline.
+type retryAfterTransport struct {
+	base     http.RoundTripper
+	deadline time.Time
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if !ok || time.Now().Add(delay).After(t.deadline) {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	} else if req.Body != nil && req.Body != http.NoBody {
+		// Body was consumed by first attempt and can't be sent again
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// parseRetryAfter parses Retry-After header given in seconds
+func parseRetryAfter(value string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	delay := time.Duration(seconds) * time.Second
+	return delay, delay <= maxRetryAfter
+}
+
+// gzipTransport compresses request bodies larger than threshold once server
+// advertised support of gzip encoding with Accept-Encoding response header.
+// Requests are sent uncompressed until then.
+type gzipTransport struct {
+	base      http.RoundTripper
+	threshold int
+	accepted  *atomic.Bool
+}
+
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.accepted.Load() && req.GetBody != nil && req.ContentLength >= int64(t.threshold) {
+		compressed, err := gzipRequest(req)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = compressed
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(strings.ToLower(resp.Header.Get("Accept-Encoding")), "gzip") {
+		t.accepted.Store(true)
+	}
+	return resp, nil
+}
+
+// gzipRequest returns copy of request with gzip compressed body
+func gzipRequest(req *http.Request) (*http.Request, error) {
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := io.Copy(writer, body); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	compressed := req.Clone(req.Context())
+	content := buf.Bytes()
+	compressed.Body = io.NopCloser(bytes.NewReader(content))
+	compressed.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	compressed.ContentLength = int64(len(content))
+	compressed.Header.Set("Content-Encoding", "gzip")
+	return compressed, nil
+}
+
+// newTransport creates transport limiting time of connection establishment
+// separately from overall request timeout, so slow body reads of large tables
+// don't require long connect timeout. Protocols are set by HTTP/2 mode.
+func newTransport(config *models.PluginSettings) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{KeepAlive: 30 * time.Second}
+	transport.DialContext = dialWithTimeout(dialer.DialContext, config.ConnectTimeout())
+
+	protocols := new(http.Protocols)
+	switch config.HTTP2 {
+	case models.HTTP2Disabled:
+		protocols.SetHTTP1(true)
+	case models.HTTP2Forced:
+		// Multiplexing many parallel dashboard requests over single connection
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	}
+	transport.Protocols = protocols
+	return transport
+}
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// dialWithTimeout limits time given dial function may spend establishing connection
+func dialWithTimeout(dial dialFunc, timeout time.Duration) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		conn, err := dial(dialCtx, network, address)
+		if err != nil && ctx.Err() == nil && errors.Is(dialCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("connect timeout after %v: %w", timeout, err)
+		}
+		return conn, err
+	}
+}
+
+// limitedTransport waits for free slot before sending request. Slot is held
+// until response body is closed, so reading of response is limited as well.
+type limitedTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	var once sync.Once
+	release := func() { once.Do(func() { <-t.slots }) }
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody frees request slot when response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// readResponseBody reads upstream response body. When lenient JSON parsing is
+// enabled, known quirks of older servers (trailing commas, bare NaN and
+// Infinity numbers) are removed.
+func readResponseBody(body io.Reader, config *models.PluginSettings) ([]byte, error) {
+	content, err := io.ReadAll(body)
+	if err != nil || !config.LenientJSON {
+		return content, err
+	}
+	return replaceNonFiniteNumbers(removeTrailingCommas(content)), nil
+}
+
+// nonFiniteTokens are out-of-spec JSON number tokens emitted by some servers,
+// longest first so that prefix doesn't shadow full token
+var nonFiniteTokens = []string{"-Infinity", "Infinity", "NaN"}
+
+// replaceNonFiniteNumbers quotes bare NaN and Infinity tokens outside of JSON
+// strings, so they are parsed like NaN and Infinity strings sent by other
+// servers and become nulls in numeric fields
+func replaceNonFiniteNumbers(body []byte) []byte {
+	result := make([]byte, 0, len(body))
+	inString := false
+	escaped := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			result = append(result, c)
+			continue
+		}
+		if c == '"' {
+			inString = true
+		} else if c == '-' || c == 'I' || c == 'N' {
+			replaced := false
+			for _, token := range nonFiniteTokens {
+				if bytes.HasPrefix(body[i:], []byte(token)) {
+					result = append(result, '"')
+					result = append(result, token...)
+					result = append(result, '"')
+					i += len(token) - 1
+					replaced = true
+					break
+				}
+			}
+			if replaced {
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// removeTrailingCommas drops commas directly preceding closing bracket or brace
+// outside of JSON strings
+func removeTrailingCommas(body []byte) []byte {
+	result := make([]byte, 0, len(body))
+	inString := false
+	escaped := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			result = append(result, c)
+			continue
+		}
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			next := i + 1
+			for next < len(body) && strings.IndexByte(" \t\r\n", body[next]) >= 0 {
+				next++
+			}
+			if next < len(body) && (body[next] == '}' || body[next] == ']') {
+				continue
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// rawJSONFrame wraps unmodified server response into single cell frame for debugging
+func rawJSONFrame(name string, body []byte) *data.Frame {
+	return data.NewFrame(name, data.NewField("raw", nil, []string{string(body)}))
+}
+
+// isEmptyResponse checks if server returned no content that should be shown as
+// empty result. When query allows empty responses, 204 and empty body with
+// healthy status are accepted. Empty body with status other than 200 is always
+// accepted if that status is configured as healthy.
+func isEmptyResponse(allowEmpty bool, statusCode int, body []byte, config *models.PluginSettings) bool {
+	if statusCode != http.StatusNoContent && len(bytes.TrimSpace(body)) != 0 {
+		return false
+	}
+	if allowEmpty && statusCode == http.StatusNoContent {
+		return true
+	}
+	if !config.IsHealthyStatus(statusCode) {
+		return false
+	}
+	return allowEmpty || statusCode != http.StatusOK
+}
+
+// handleUpstreamError converts unsuccessful upstream response into error
+// response. Second return value is false if response status is configured as healthy.
+func handleUpstreamError(statusCode int, body []byte, config *models.PluginSettings) (backend.DataResponse, bool) {
+	if statusCode == http.StatusUnauthorized {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "Unauthorized: Invalid API key"), true
+	}
+	if !config.IsHealthyStatus(statusCode) {
+		return parseErrorResponse(statusCode, body), true
+	}
+	return backend.DataResponse{}, false
+}
+
+// parseErrorResponse extracts error message from response body and returns appropriate DataResponse
+func parseErrorResponse(statusCode int, body []byte) backend.DataResponse {
+	if reason := errorReason(decodeErrorBody(body)); reason != "" {
+		return backend.ErrDataResponse(httpStatusToBackendStatus(statusCode), "Request error: "+reason)
+	}
+	return backend.ErrDataResponse(httpStatusToBackendStatus(statusCode), "Request error")
+}
+
+// maxErrorReasonLength limits length of plain text error body shown to user
+const maxErrorReasonLength = 256
+
+// errorReason extracts error message from body given as {"reason": ...} or
+// {"message": ...} object, JSON string or plain text. HTML pages (e.g. from
+// reverse proxies) are ignored. Empty string is returned if body has no message.
+func errorReason(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] == '<' {
+		return ""
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(trimmed, &object); err == nil {
+		for _, key := range []string{"reason", "message"} {
+			if reason, ok := object[key].(string); ok && strings.TrimSpace(reason) != "" {
+				return strings.TrimSpace(reason)
+			}
+		}
+		return ""
+	}
+
+	var reason string
+	if err := json.Unmarshal(trimmed, &reason); err == nil {
+		return strings.TrimSpace(reason)
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		return ""
+	}
+
+	reason = string(trimmed)
+	if runes := []rune(reason); len(runes) > maxErrorReasonLength {
+		reason = string(runes[:maxErrorReasonLength]) + "..."
+	}
+	return reason
+}
+
+// decodeErrorBody decompresses gzip-encoded error body, which is not decoded
+// by HTTP client when server compresses response without being asked to
+func decodeErrorBody(body []byte) []byte {
+	if len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+		return body
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return body
+	}
+	defer reader.Close()
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return body
+	}
+	return decoded
+}
+
+// httpStatusToBackendStatus maps HTTP status codes to backend.Status
+func httpStatusToBackendStatus(code int) backend.Status {
+	if code == 400 {
+		return backend.StatusBadRequest
+	}
+	if code == 401 {
+		return backend.StatusUnauthorized
+	}
+	if code == 403 {
+		return backend.StatusForbidden
+	}
+	if code == 404 {
+		return backend.StatusNotFound
+	}
+	if code >= 500 && code < 600 {
+		return backend.StatusInternal
+	}
+	return backend.StatusUnknown
+}
+
+func writeJSONResponse(rw http.ResponseWriter, body []byte) {
+	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(body)
+}
+
+// writeJSONError writes error as {"error": message} so frontend can parse it
+func writeJSONError(rw http.ResponseWriter, status int, message string) {
+	body, err := json.Marshal(map[string]string{"error": message})
+	if err != nil {
+		http.Error(rw, message, status)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(body)
+}
+
+func joinURL(base, path string) string {
+	base = strings.TrimRight(base, "/")
+	path = strings.TrimLeft(path, "/")
+	return base + "/" + path
+}
@@ -0,0 +1,551 @@
+package plugin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/raden-solutions/net-xms/pkg/models"
+)
+
+func TestNewHTTPClientRespectsContextDeadline(t *testing.T) {
+	config := &models.PluginSettings{Timeout: 30}
+	if client := newHTTPClient(context.Background(), config); client.Timeout != 30*time.Second {
+		t.Errorf("Expected configured timeout, got: %v", client.Timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if client := newHTTPClient(ctx, config); client.Timeout > 2*time.Second {
+		t.Errorf("Expected timeout limited by context deadline, got: %v", client.Timeout)
+	}
+}
+
+func TestGzipEncodedErrorResponse(t *testing.T) {
+	var payload bytes.Buffer
+	writer := gzip.NewWriter(&payload)
+	_, _ = writer.Write([]byte(`{"reason":"Access denied"}`))
+	_ = writer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write(payload.Bytes())
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{"sourceObjectId": "1"}`)
+	if response.Error == nil {
+		t.Fatal("Expected error response")
+	}
+	if response.Error.Error() != "Request error: Access denied" {
+		t.Errorf("Expected decoded reason, got: %v", response.Error)
+	}
+}
+
+func TestAPIVersionHeader(t *testing.T) {
+	var version string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = r.Header.Get(apiVersionHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	runTestQuery(t, newTestSettings(server.URL), "alarms", `{"sourceObjectId": "1"}`)
+	if version != models.DefaultAPIVersion {
+		t.Errorf("Expected default API version %q, got: %q", models.DefaultAPIVersion, version)
+	}
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "apiVersion": "2"}`),
+	}
+	runTestQuery(t, settings, "alarms", `{"sourceObjectId": "1"}`)
+	if version != "2" {
+		t.Errorf("Expected configured API version 2, got: %q", version)
+	}
+}
+
+func TestNewAuthenticatedRequest(t *testing.T) {
+	config := &models.PluginSettings{ServerAddress: "http://netxms.local", Secrets: &models.SecretPluginSettings{ApiKey: "test-key"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	request, err := newAuthenticatedRequest(ctx, http.MethodPost, "http://netxms.local/v1/test", []byte(`{"a":1}`), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Header.Get("Authorization") != "Bearer test-key" {
+		t.Errorf("Unexpected Authorization header: %q", request.Header.Get("Authorization"))
+	}
+	if request.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got: %q", request.Header.Get("Content-Type"))
+	}
+	if request.Header.Get(apiVersionHeader) != models.DefaultAPIVersion {
+		t.Errorf("Expected default API version header, got: %q", request.Header.Get(apiVersionHeader))
+	}
+	if request.Context() != ctx {
+		t.Error("Expected request to carry caller context")
+	}
+
+	request, err = newAuthenticatedRequest(ctx, http.MethodGet, "http://netxms.local/v1/test", nil, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Header.Get("Content-Type") != "" {
+		t.Errorf("Expected no content type without body, got: %q", request.Header.Get("Content-Type"))
+	}
+	if request.Body != http.NoBody {
+		t.Error("Expected empty body for request without body")
+	}
+}
+
+func TestHandleUpstreamError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		failed     bool
+		status     backend.Status
+		message    string
+	}{
+		{"ok", http.StatusOK, `[]`, false, 0, ""},
+		{"unauthorized", http.StatusUnauthorized, `{"reason":"bad key"}`, true, backend.StatusUnauthorized, "Unauthorized: Invalid API key"},
+		{"forbidden with reason", http.StatusForbidden, `{"reason":"Access denied"}`, true, backend.StatusForbidden, "Request error: Access denied"},
+		{"not found with plain text reason", http.StatusNotFound, `not found`, true, backend.StatusNotFound, "Request error: not found"},
+		{"server error", http.StatusInternalServerError, `{"reason":"Internal error"}`, true, backend.StatusInternal, "Request error: Internal error"},
+		{"no content", http.StatusNoContent, ``, true, backend.StatusUnknown, "Request error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, failed := handleUpstreamError(tt.statusCode, []byte(tt.body), &models.PluginSettings{})
+			if failed != tt.failed {
+				t.Fatalf("Expected failed=%v, got: %v", tt.failed, failed)
+			}
+			if !failed {
+				return
+			}
+			if res.Status != tt.status {
+				t.Errorf("Expected status %v, got: %v", tt.status, res.Status)
+			}
+			if res.Error == nil || res.Error.Error() != tt.message {
+				t.Errorf("Expected error %q, got: %v", tt.message, res.Error)
+			}
+		})
+	}
+}
+
+func TestConcurrentRequestLimit(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := maxInFlight.Load()
+			if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "maxConcurrentRequests": 2}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+	ds := newTestDatasource(t, settings)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := ds.QueryData(context.Background(), &backend.QueryDataRequest{
+				PluginContext: backend.PluginContext{DataSourceInstanceSettings: &settings},
+				Queries:       []backend.DataQuery{{RefID: "A", QueryType: "alarms", JSON: []byte(`{}`)}},
+			})
+			if err != nil || resp.Responses["A"].Error != nil {
+				t.Errorf("Unexpected query failure: %v %v", err, resp.Responses["A"].Error)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got: %d", maxInFlight.Load())
+	}
+}
+
+func TestConcurrentRequestLimitRespectsContext(t *testing.T) {
+	slots := make(chan struct{}, 1)
+	slots <- struct{}{}
+	transport := &limitedTransport{base: http.DefaultTransport, slots: slots}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://netxms.local/", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.RoundTrip(request); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded while waiting for slot, got: %v", err)
+	}
+}
+
+func TestLenientJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Name":"node1, rack 2,]","Status":"ok",},{"Name":"node\"2,}","Status":"down" ,
+		},]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error == nil {
+		t.Fatal("Expected parse error without lenient JSON parsing")
+	}
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "lenientJson": true}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+	response = runTestQuery(t, settings, "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected trailing commas to be tolerated, got: %v", response.Error)
+	}
+	frame := response.Frames[0]
+	if frame.Rows() != 2 || len(frame.Fields) != 2 {
+		t.Fatalf("Expected 2 rows and 2 columns, got: %d rows, %d columns", frame.Rows(), len(frame.Fields))
+	}
+	if name, _ := frame.Fields[0].At(0).(string); name != "node1, rack 2,]" {
+		t.Errorf("Expected commas inside strings to be kept, got: %q", name)
+	}
+	if name, _ := frame.Fields[0].At(1).(string); name != `node"2,}` {
+		t.Errorf("Expected escaped quotes to be handled, got: %q", name)
+	}
+}
+
+func TestRedirectHandling(t *testing.T) {
+	var redirectedAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/grafana/infinity/alarms":
+			http.Redirect(w, r, "/api/v1/grafana/infinity/alarms", http.StatusMovedPermanently)
+		case "/api/v1/grafana/infinity/alarms":
+			redirectedAuth = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			// Redirect to the same server under different host name
+			port := strings.TrimPrefix(r.Host[strings.LastIndex(r.Host, ":"):], ":")
+			http.Redirect(w, r, "http://localhost:"+port+"/api/v1/grafana/infinity/alarms", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "alarms", `{}`)
+	if response.Error != nil {
+		t.Fatalf("Expected same-host redirect to be followed, got: %v", response.Error)
+	}
+	if redirectedAuth != "Bearer test-key" {
+		t.Errorf("Expected authorization header to be preserved, got: %q", redirectedAuth)
+	}
+
+	redirectedAuth = ""
+	response = runTestQuery(t, newTestSettings(server.URL+"/other"), "alarms", `{}`)
+	if response.Error == nil || !strings.Contains(response.Error.Error(), "redirected to different host") {
+		t.Errorf("Expected clear error for cross-host redirect, got: %v", response.Error)
+	}
+	if redirectedAuth != "" {
+		t.Error("Expected cross-host redirect not to be followed")
+	}
+}
+
+func TestRedirectSchemeDowngrade(t *testing.T) {
+	initial := httptest.NewRequest(http.MethodGet, "https://netxms.local/v1/alarms", nil)
+	initial.Header.Set("Authorization", "Bearer test-key")
+
+	downgraded := httptest.NewRequest(http.MethodGet, "http://netxms.local/api/v1/alarms", nil)
+	if err := checkRedirect(downgraded, []*http.Request{initial}); err == nil || !strings.Contains(err.Error(), "unencrypted") {
+		t.Errorf("Expected https to http redirect to be refused, got: %v", err)
+	}
+	if auth := downgraded.Header.Get("Authorization"); auth != "" {
+		t.Errorf("Expected no authorization on downgraded request, got: %q", auth)
+	}
+
+	// Downgrade after intermediate https redirect is refused as well
+	intermediate := httptest.NewRequest(http.MethodGet, "https://netxms.local/api/v1/alarms", nil)
+	plainInitial := httptest.NewRequest(http.MethodGet, "http://netxms.local/v1/alarms", nil)
+	if err := checkRedirect(downgraded, []*http.Request{plainInitial, intermediate}); err == nil {
+		t.Error("Expected downgrade after https hop to be refused")
+	}
+
+	upgraded := httptest.NewRequest(http.MethodGet, "https://netxms.local/api/v1/alarms", nil)
+	plainInitial.Header.Set("Authorization", "Bearer test-key")
+	if err := checkRedirect(upgraded, []*http.Request{plainInitial}); err != nil {
+		t.Errorf("Expected http to https upgrade to be followed, got: %v", err)
+	}
+	if auth := upgraded.Header.Get("Authorization"); auth != "Bearer test-key" {
+		t.Errorf("Expected authorization to be kept on upgrade, got: %q", auth)
+	}
+}
+
+func TestDialTimeout(t *testing.T) {
+	// Dial that never completes, as with unreachable server dropping packets
+	hangingDial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{DialContext: dialWithTimeout(hangingDial, 100*time.Millisecond)},
+	}
+	start := time.Now()
+	_, err := client.Get("http://netxms.local/v1/server-info")
+	if err == nil {
+		t.Fatal("Expected connect timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected dial timeout to trigger before overall timeout, took: %v", elapsed)
+	}
+	if !strings.Contains(err.Error(), "connect timeout") {
+		t.Errorf("Expected connect timeout error, got: %v", err)
+	}
+}
+
+func TestGzipRequestBody(t *testing.T) {
+	var encodings []string
+	var parameters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			reader = gz
+		}
+		var body map[string]any
+		_ = json.NewDecoder(reader).Decode(&body)
+		if values, ok := body["values"].([]any); ok {
+			parameters = append(parameters, fmt.Sprint(len(values)))
+		}
+		w.Header().Set("Accept-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	params := make([]map[string]string, 200)
+	for i := range params {
+		params[i] = map[string]string{fmt.Sprintf("param%d", i): strings.Repeat("x", 20)}
+	}
+	paramsJSON, _ := json.Marshal(params)
+	query, _ := json.Marshal(map[string]string{"objectQueryId": "1", "queryParameters": string(paramsJSON)})
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"serverAddress": "` + server.URL + `", "gzipRequests": true, "gzipRequestThreshold": 1024}`),
+	}
+	ds := newTestDatasource(t, settings)
+	for range 2 {
+		if response := runDatasourceQuery(t, ds, settings, "objectQueries", string(query)); response.Error != nil {
+			t.Fatalf("Expected no error, got: %v", response.Error)
+		}
+	}
+	if len(encodings) != 2 || encodings[0] != "" || encodings[1] != "gzip" {
+		t.Errorf("Expected body to be compressed once server advertised gzip support, got encodings: %q", encodings)
+	}
+	if len(parameters) != 2 || parameters[1] != "200" {
+		t.Errorf("Expected compressed body to contain all parameters, got: %v", parameters)
+	}
+
+	// Small bodies are never compressed
+	if response := runDatasourceQuery(t, ds, settings, "objectQueries", `{"objectQueryId": "1"}`); response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	if encodings[2] != "" {
+		t.Error("Expected small request body to be sent uncompressed")
+	}
+}
+
+func TestTransportHTTP2Mode(t *testing.T) {
+	tests := []struct {
+		mode      string
+		http1     bool
+		http2     bool
+		plainHTTP bool
+	}{
+		{models.HTTP2Auto, true, true, false},
+		{models.HTTP2Disabled, true, false, false},
+		{models.HTTP2Forced, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			protocols := newTransport(&models.PluginSettings{HTTP2: tt.mode}).Protocols
+			if protocols.HTTP1() != tt.http1 || protocols.HTTP2() != tt.http2 || protocols.UnencryptedHTTP2() != tt.plainHTTP {
+				t.Errorf("Unexpected protocols for %s mode: %v", tt.mode, protocols)
+			}
+		})
+	}
+}
+
+func TestParseErrorResponseBodyShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"reason", `{"reason": "Access denied"}`, "Request error: Access denied"},
+		{"message", `{"message": "Object not found", "code": 404}`, "Request error: Object not found"},
+		{"reason preferred", `{"message": "Generic", "reason": "Specific"}`, "Request error: Specific"},
+		{"JSON string", `"Invalid object id"`, "Request error: Invalid object id"},
+		{"plain text", "Internal server error\n", "Request error: Internal server error"},
+		{"HTML page", "<html><body>Bad gateway</body></html>", "Request error"},
+		{"object without message", `{"code": 500}`, "Request error"},
+		{"malformed JSON", `{"reason": `, "Request error"},
+		{"empty", "", "Request error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := parseErrorResponse(http.StatusNotFound, []byte(tt.body))
+			if response.Error == nil || response.Error.Error() != tt.expected {
+				t.Errorf("Expected error %q, got: %v", tt.expected, response.Error)
+			}
+		})
+	}
+
+	long := strings.Repeat("x", maxErrorReasonLength+10)
+	if message := parseErrorResponse(http.StatusInternalServerError, []byte(long)).Error.Error(); len(message) != len("Request error: ")+maxErrorReasonLength+3 {
+		t.Errorf("Expected truncated plain text reason, got %d characters", len(message))
+	}
+}
+
+func TestRetryAfterRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		settings   string
+		requests   int32
+		success    bool
+	}{
+		{"retried", "0", `{"timeout": 5}`, 2, true},
+		{"delay beyond timeout", "10", `{"timeout": 2}`, 1, false},
+		{"invalid header", "soon", `{"timeout": 5}`, 1, false},
+		{"disabled", "0", `{"timeout": 5, "retryOnRateLimit": false}`, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if requests.Add(1) == 1 {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				if string(body) != `{"rootObjectId":1}` {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			settings := newTestSettings(server.URL)
+			settings.JSONData = []byte(strings.Replace(tt.settings, "{", `{"serverAddress": "`+server.URL+`", `, 1))
+			response := runTestQuery(t, settings, "alarms", `{"sourceObjectId": "1"}`)
+			if (response.Error == nil) != tt.success {
+				t.Errorf("Expected success=%v, got error: %v", tt.success, response.Error)
+			}
+			if requests.Load() != tt.requests {
+				t.Errorf("Expected %d requests, got: %d", tt.requests, requests.Load())
+			}
+		})
+	}
+}
+
+func TestNonFiniteValuesAsNulls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "history") {
+			_, _ = w.Write([]byte(`{"values":[` +
+				`{"timestamp":"2026-01-01T00:00:00Z","value":"1.5"},` +
+				`{"timestamp":"2026-01-01T00:01:00Z","value":"NaN"},` +
+				`{"timestamp":"2026-01-01T00:02:00Z","value":"Infinity"},` +
+				`{"timestamp":"2026-01-01T00:03:00Z","value":"-Infinity"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"Name":"node1","Load":NaN},{"Name":"node2","Load":0.5},{"Name":"NaN","Load":-Infinity}]`))
+	}))
+	defer server.Close()
+
+	response := runTestQuery(t, newTestSettings(server.URL), "dciValues", `{"sourceObjectId": "1", "dciId": "2"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected no error, got: %v", response.Error)
+	}
+	values := response.Frames[0].Fields[1]
+	if values.Type() != data.FieldTypeNullableFloat64 {
+		t.Fatalf("Expected nullable numeric field, got: %v", values.Type())
+	}
+	if v, _ := values.At(0).(*float64); v == nil || *v != 1.5 {
+		t.Errorf("Expected finite value to be kept, got: %v", v)
+	}
+	for i := 1; i < values.Len(); i++ {
+		if v, _ := values.At(i).(*float64); v != nil {
+			t.Errorf("Expected non-finite value %d to become null, got: %v", i, *v)
+		}
+	}
+
+	response = runTestQuery(t, newTestSettings(server.URL), "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error == nil {
+		t.Fatal("Expected parse error for bare NaN without lenient JSON parsing")
+	}
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                []byte(`{"serverAddress": "` + server.URL + `", "lenientJson": true}`),
+		DecryptedSecureJSONData: map[string]string{"apiKey": "test-key"},
+	}
+	response = runTestQuery(t, settings, "objectQueries", `{"objectQueryId": "1"}`)
+	if response.Error != nil {
+		t.Fatalf("Expected bare NaN and Infinity to be tolerated, got: %v", response.Error)
+	}
+	load, _ := response.Frames[0].FieldByName("Load")
+	if load.Type() != data.FieldTypeNullableFloat64 {
+		t.Fatalf("Expected numeric Load column, got: %v", load.Type())
+	}
+	if v, _ := load.At(0).(*float64); v != nil {
+		t.Errorf("Expected NaN to become null, got: %v", *v)
+	}
+	if v, _ := load.At(1).(*float64); v == nil || *v != 0.5 {
+		t.Errorf("Expected finite value to be kept, got: %v", v)
+	}
+	if v, _ := load.At(2).(*float64); v != nil {
+		t.Errorf("Expected -Infinity to become null, got: %v", *v)
+	}
+	if name, _ := response.Frames[0].Fields[0].At(2).(string); name != "NaN" {
+		t.Errorf("Expected NaN string in text column to be kept, got: %q", name)
+	}
+}
@@ -1,10 +1,25 @@
 import React, { ChangeEvent } from 'react';
-import { InlineField, Input, SecretInput } from '@grafana/ui';
-import { DataSourcePluginOptionsEditorProps } from '@grafana/data';
+import { InlineField, InlineSwitch, Input, SecretInput, Select } from '@grafana/ui';
+import { DataSourcePluginOptionsEditorProps, SelectableValue } from '@grafana/data';
 import { NetxmsSourceOptions, NetXMSSecureJsonData } from '../types';
 
 interface Props extends DataSourcePluginOptionsEditorProps<NetxmsSourceOptions, NetXMSSecureJsonData> {}
 
+type NumberOption = 'connectTimeout' | 'objectQueryCacheTTL' | 'slowResponseThresholdMs' | 'maxConcurrentRequests' | 'maxQueriesPerRequest' | 'gzipRequestThreshold';
+type SwitchOption = 'retryOnRateLimit' | 'gzipRequests' | 'lenientJson';
+type TextOption = 'apiVersion' | 'serverTimezone';
+
+const http2Options: Array<SelectableValue<NetxmsSourceOptions['http2']>> = [
+  { label: 'Auto', value: 'auto', description: 'HTTP/2 over TLS when server supports it' },
+  { label: 'Disabled', value: 'disabled', description: 'Only HTTP/1.1' },
+  { label: 'Forced', value: 'forced', description: 'Only HTTP/2, also over plain HTTP' },
+];
+
+const failureModeOptions: Array<SelectableValue<NetxmsSourceOptions['failureMode']>> = [
+  { label: 'Error', value: 'error', description: 'Failed query shows error' },
+  { label: 'Empty', value: 'empty', description: 'Failed query returns empty frame with error notice' },
+];
+
 export function ConfigEditor(props: Props) {
   const { onOptionsChange, options } = props;
   const { jsonData, secureJsonFields, secureJsonData } = options;
@@ -19,6 +34,116 @@ export function ConfigEditor(props: Props) {
     });
   };
 
+  const onSortObjectsChange = (event: React.FormEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        sortObjects: event.currentTarget.checked,
+      },
+    });
+  };
+
+  const onShowObjectPathsChange = (event: React.FormEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        showObjectPaths: event.currentTarget.checked,
+      },
+    });
+  };
+
+  const onAllowAlarmAcknowledgeChange = (event: React.FormEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        allowAlarmAcknowledge: event.currentTarget.checked,
+      },
+    });
+  };
+
+  const onTimeoutChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        timeout: event.target.value ? Number(event.target.value) : undefined,
+      },
+    });
+  };
+
+  const onDefaultTimeRangeChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        defaultTimeRange: event.target.value ? Number(event.target.value) : undefined,
+      },
+    });
+  };
+
+  const onNumberOptionChange = (key: NumberOption) => (event: ChangeEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        [key]: event.target.value ? Number(event.target.value) : undefined,
+      },
+    });
+  };
+
+  const onSwitchOptionChange = (key: SwitchOption) => (event: React.FormEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        [key]: event.currentTarget.checked,
+      },
+    });
+  };
+
+  const onTextOptionChange = (key: TextOption) => (event: ChangeEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        [key]: event.target.value.trim() || undefined,
+      },
+    });
+  };
+
+  const onHTTP2Change = (option: SelectableValue<NetxmsSourceOptions['http2']>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        http2: option?.value,
+      },
+    });
+  };
+
+  const onFailureModeChange = (option: SelectableValue<NetxmsSourceOptions['failureMode']>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        failureMode: option?.value,
+      },
+    });
+  };
+
+  const onWebUIAddressChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onOptionsChange({
+      ...options,
+      jsonData: {
+        ...jsonData,
+        webUiAddress: event.target.value,
+      },
+    });
+  };
+
   // Secure field (only sent to the backend)
   const onAPIKeyChange = (event: ChangeEvent<HTMLInputElement>) => {
     onOptionsChange({
@@ -45,7 +170,7 @@ export function ConfigEditor(props: Props) {
 
   return (
     <>
-      <InlineField label="API address" labelWidth={14} interactive tooltip={'Base URL of the NetXMS server'}>
+      <InlineField label="API address" labelWidth={14} interactive tooltip={'Base URL of the NetXMS server, including the http:// or https:// scheme'}>
         <Input
           required
           id="config-editor-path"
@@ -67,6 +192,175 @@ export function ConfigEditor(props: Props) {
           onChange={onAPIKeyChange}
         />
       </InlineField>
+      <InlineField label="Timeout" labelWidth={14} interactive tooltip={'Request timeout in seconds'}>
+        <Input
+          id="config-editor-timeout"
+          type="number"
+          onChange={onTimeoutChange}
+          value={jsonData.timeout ?? ''}
+          placeholder="10"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Time range" labelWidth={14} interactive tooltip={'Time range in seconds used by DCI queries sent without time range, e.g. from alerting'}>
+        <Input
+          id="config-editor-default-time-range"
+          type="number"
+          onChange={onDefaultTimeRangeChange}
+          value={jsonData.defaultTimeRange ?? ''}
+          placeholder="3600"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Web UI address" labelWidth={14} interactive tooltip={'Base URL of the NetXMS web UI used for drill-down links, leave empty to disable links'}>
+        <Input
+          id="config-editor-web-ui-address"
+          onChange={onWebUIAddressChange}
+          value={jsonData.webUiAddress ?? ''}
+          placeholder="e.g. https://netxms.server.com/nxmc"
+          width={60}
+        />
+      </InlineField>
+      <InlineField label="Sort objects" labelWidth={14} interactive tooltip={'Sort object lists by name instead of keeping server order'}>
+        <InlineSwitch
+          id="config-editor-sort-objects"
+          value={jsonData.sortObjects ?? true}
+          onChange={onSortObjectsChange}
+        />
+      </InlineField>
+      <InlineField label="Object paths" labelWidth={14} interactive tooltip={'Show full object path (e.g. Site/Rack/Node) in object lists'}>
+        <InlineSwitch
+          id="config-editor-show-object-paths"
+          value={jsonData.showObjectPaths ?? false}
+          onChange={onShowObjectPathsChange}
+        />
+      </InlineField>
+      <InlineField label="Alarm ack" labelWidth={14} interactive tooltip={'Allow Grafana editors and admins to acknowledge alarms using the API key of this datasource'}>
+        <InlineSwitch
+          id="config-editor-allow-alarm-acknowledge"
+          value={jsonData.allowAlarmAcknowledge ?? false}
+          onChange={onAllowAlarmAcknowledgeChange}
+        />
+      </InlineField>
+      <InlineField label="Connect timeout" labelWidth={14} interactive tooltip={'Connection establishment timeout in seconds'}>
+        <Input
+          id="config-editor-connect-timeout"
+          type="number"
+          onChange={onNumberOptionChange('connectTimeout')}
+          value={jsonData.connectTimeout ?? ''}
+          placeholder="5"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="HTTP/2" labelWidth={14} interactive tooltip={'HTTP/2 usage for requests to NetXMS server'}>
+        <Select
+          inputId="config-editor-http2"
+          options={http2Options}
+          value={jsonData.http2 ?? 'auto'}
+          onChange={onHTTP2Change}
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Retry on 429" labelWidth={14} interactive tooltip={'Retry rate limited request once after delay requested by server, within request timeout'}>
+        <InlineSwitch
+          id="config-editor-retry-on-rate-limit"
+          value={jsonData.retryOnRateLimit ?? true}
+          onChange={onSwitchOptionChange('retryOnRateLimit')}
+        />
+      </InlineField>
+      <InlineField label="Query cache" labelWidth={14} interactive tooltip={'Time in seconds object query results are cached, 0 disables caching'}>
+        <Input
+          id="config-editor-object-query-cache-ttl"
+          type="number"
+          onChange={onNumberOptionChange('objectQueryCacheTTL')}
+          value={jsonData.objectQueryCacheTTL ?? ''}
+          placeholder="0"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="API version" labelWidth={14} interactive tooltip={'Grafana API version requested from NetXMS server'}>
+        <Input
+          id="config-editor-api-version"
+          onChange={onTextOptionChange('apiVersion')}
+          value={jsonData.apiVersion ?? ''}
+          placeholder="1"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Slow response" labelWidth={14} interactive tooltip={'Response time in milliseconds after which query result gets slow response notice'}>
+        <Input
+          id="config-editor-slow-response-threshold"
+          type="number"
+          onChange={onNumberOptionChange('slowResponseThresholdMs')}
+          value={jsonData.slowResponseThresholdMs ?? ''}
+          placeholder="5000"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="On failure" labelWidth={14} interactive tooltip={'Result of failed query, empty frame keeps other panels of dashboard working'}>
+        <Select
+          inputId="config-editor-failure-mode"
+          options={failureModeOptions}
+          value={jsonData.failureMode ?? 'error'}
+          onChange={onFailureModeChange}
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Max requests" labelWidth={14} interactive tooltip={'Maximum number of concurrent requests to NetXMS server'}>
+        <Input
+          id="config-editor-max-concurrent-requests"
+          type="number"
+          onChange={onNumberOptionChange('maxConcurrentRequests')}
+          value={jsonData.maxConcurrentRequests ?? ''}
+          placeholder="10"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Max queries" labelWidth={14} interactive tooltip={'Maximum number of queries processed per request, excess queries fail'}>
+        <Input
+          id="config-editor-max-queries-per-request"
+          type="number"
+          onChange={onNumberOptionChange('maxQueriesPerRequest')}
+          value={jsonData.maxQueriesPerRequest ?? ''}
+          placeholder="100"
+          width={20}
+        />
+      </InlineField>
+      <InlineField label="Gzip requests" labelWidth={14} interactive tooltip={'Compress large request bodies once server advertises gzip support'}>
+        <InlineSwitch
+          id="config-editor-gzip-requests"
+          value={jsonData.gzipRequests ?? false}
+          onChange={onSwitchOptionChange('gzipRequests')}
+        />
+      </InlineField>
+      {jsonData.gzipRequests && (
+        <InlineField label="Gzip threshold" labelWidth={14} interactive tooltip={'Minimum request body size in bytes to compress'}>
+          <Input
+            id="config-editor-gzip-request-threshold"
+            type="number"
+            onChange={onNumberOptionChange('gzipRequestThreshold')}
+            value={jsonData.gzipRequestThreshold ?? ''}
+            placeholder="65536"
+            width={20}
+          />
+        </InlineField>
+      )}
+      <InlineField label="Lenient JSON" labelWidth={14} interactive tooltip={'Remove trailing commas and rewrite bare NaN/Infinity in responses of legacy servers, may hide malformed responses'}>
+        <InlineSwitch
+          id="config-editor-lenient-json"
+          value={jsonData.lenientJson ?? false}
+          onChange={onSwitchOptionChange('lenientJson')}
+        />
+      </InlineField>
+      <InlineField label="Time zone" labelWidth={14} interactive tooltip={'Time zone of NetXMS server used for DCI history time bounds, e.g. Europe/Riga'}>
+        <Input
+          id="config-editor-server-timezone"
+          onChange={onTextOptionChange('serverTimezone')}
+          value={jsonData.serverTimezone ?? ''}
+          placeholder="UTC"
+          width={20}
+        />
+      </InlineField>
     </>
   );
 }
@@ -3,6 +3,7 @@ import { InlineField, Stack, Combobox, Select } from '@grafana/ui';
 import { QueryEditorProps, SelectableValue } from '@grafana/data';
 import { DataSource } from '../datasource';
 import { NetxmsSourceOptions as NetXMSDataSourceOptions, NetXMSQuery } from '../types';
+import { QueryOptions } from './QueryOptions';
 
 type Props = QueryEditorProps<DataSource, NetXMSQuery, NetXMSDataSourceOptions>;
 
@@ -31,7 +32,10 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
       let response;
       switch (type) {
         case 'objectStatus':
+        case 'objectChildren':
         case 'alarms':
+        case 'alarmCount':
+        case 'alarmHistory':
           response = await datasource.getAlarmObjectList();
           break;
         case 'summaryTables':
@@ -62,10 +66,10 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
     }
   }, [datasource, formatOptions]);
 
-  const loadSummaryTableList = useCallback(async () => {
+  const loadSummaryTableList = useCallback(async (objectId?: string) => {
     setIsLoadingSummaryTable(true);
     try {
-      const response = await datasource.getSummaryTableList();
+      const response = await datasource.getSummaryTableList(objectId);
       setSummaryTableList(formatOptions(response));
     } finally {
       setIsLoadingSummaryTable(false);
@@ -92,9 +96,15 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
       case 'alarms':
         loadObjectList('alarms');
         break;
+      case 'alarmCount':
+        loadObjectList('alarmCount');
+        break;
+      case 'alarmHistory':
+        loadObjectList('alarmHistory');
+        break;
       case 'summaryTables':
         loadObjectList('summaryTables');
-        loadSummaryTableList();
+        loadSummaryTableList(query.sourceObjectId);
         break;
       case 'objectQueries':
         loadObjectList('objectQueries');
@@ -109,6 +119,9 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
       case 'objectStatus':
         loadObjectList('objectStatus');
         break;
+      case 'objectChildren':
+        loadObjectList('objectChildren');
+        break;
     }
   }, [query.queryType, query.sourceObjectId, loadObjectList, loadSummaryTableList, loadObjectQueryList, loadDciList]);
 
@@ -120,12 +133,19 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
     if (query.queryType === 'dciValues' && v?.value) {
       loadDciList(v.value);
     }
+    if (query.queryType === 'summaryTables' && v?.value) {
+      // Offer only summary tables applicable to selected object
+      loadSummaryTableList(v.value);
+    }
     handleOnRunQuery();
   };
 
   const handleOnRunQuery = (): void => {
     switch (query.queryType) {
       case 'alarms':
+      case 'alarmCount':
+      case 'alarmHistory':
+      case 'serverInfo':
         onRunQuery();
         break;
       case 'summaryTables':
@@ -153,6 +173,7 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
         }
         break;
       case 'objectStatus':
+      case 'objectChildren':
         if (query.sourceObjectId) {
           onRunQuery();
         }
@@ -178,6 +199,14 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
         loadObjectList('alarms');
         onRunQuery();
         break;
+      case 'alarmCount':
+        loadObjectList('alarmCount');
+        onRunQuery();
+        break;
+      case 'alarmHistory':
+        loadObjectList('alarmHistory');
+        onRunQuery();
+        break;
       case 'summaryTables':
         loadObjectList('summaryTables');
         loadSummaryTableList();
@@ -192,6 +221,12 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
       case 'objectStatus':
         loadObjectList('objectStatus');
         break;
+      case 'objectChildren':
+        loadObjectList('objectChildren');
+        break;
+      case 'serverInfo':
+        onRunQuery();
+        break;
     }
   };
 
@@ -203,17 +238,21 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
           value={query.queryType}
           options={[
             { label: 'Alarms', value: 'alarms' },
+            { label: 'Alarm Count', value: 'alarmCount' },
+            { label: 'Alarm History', value: 'alarmHistory' },
             { label: 'Summary Tables', value: 'summaryTables' },
             { label: 'Object Queries', value: 'objectQueries' },
             { label: 'DCI value', value: 'dciValues' },
             { label: 'Object Status', value: 'objectStatus' },
+            { label: 'Object Children', value: 'objectChildren' },
+            { label: 'Server Info', value: 'serverInfo' },
           ]}
           onChange={ onTypeChange }
         />
       </InlineField>
 
       {/* Optional object selector */}
-      {(query.queryType === 'alarms' || query.queryType === 'objectQueries') && (
+      {(query.queryType === 'alarms' || query.queryType === 'alarmCount' || query.queryType === 'alarmHistory' || query.queryType === 'objectQueries') && (
         <InlineField label="Root object" labelWidth={16}>
           <Select
             inputId='sourceObjectId'
@@ -229,7 +268,7 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
       )}
 
       {/* Required object selector */}
-      {(query.queryType === 'summaryTables' || query.queryType === 'dciValues' || query.queryType === 'objectStatus') && (
+      {(query.queryType === 'summaryTables' || query.queryType === 'dciValues' || query.queryType === 'objectStatus' || query.queryType === 'objectChildren') && (
         <InlineField label="Root object" labelWidth={16}>
           <Select
             value={query.sourceObjectId}
@@ -300,6 +339,8 @@ export function QueryEditor({ query, onChange, onRunQuery, datasource }: Props)
           />
         </InlineField>
       )}
+
+      <QueryOptions query={query} onChange={onChange} onRunQuery={handleOnRunQuery} />
     </Stack>
   );
 }
@@ -0,0 +1,246 @@
+import React from 'react';
+import { InlineField, InlineSwitch, Input, MultiSelect, Select, TagsInput } from '@grafana/ui';
+import { SelectableValue } from '@grafana/data';
+import { NetXMSQuery } from '../types';
+
+interface Props {
+  query: NetXMSQuery;
+  onChange: (query: NetXMSQuery) => void;
+  onRunQuery: () => void;
+}
+
+type SwitchOption =
+  | 'messageFilterRegex' | 'logFormat' | 'groupBySource' | 'withDciValues' | 'countHistory'
+  | 'flattenGroups' | 'emptyAsNull' | 'stringBooleans' | 'transpose'
+  | 'allDcis' | 'lastValue' | 'histogram' | 'statusSummary' | 'withStatusSummary'
+  | 'rawJson' | 'allowEmptyResponse';
+type TextOption = 'messageFilter' | 'ackByFilter' | 'instance' | 'seriesName' | 'seriesNameLabel' | 'timeColumn' | 'objectIdColumn' | 'frameName';
+type NumberOption = 'maxFrames' | 'depth' | 'maxPoints' | 'percentile' | 'buckets';
+type ListOption = 'fields' | 'fieldOrder';
+type MultiOption = 'severityFilter' | 'stateFilter' | 'statusFilter';
+
+const toOptions = (values: string[]): Array<SelectableValue<string>> => values.map((value) => ({ label: value, value }));
+
+const severityOptions = toOptions(['Normal', 'Warning', 'Minor', 'Major', 'Critical']);
+const stateOptions = toOptions(['Outstanding', 'Acknowledged', 'Resolved']);
+const statusOptions = toOptions(['Normal', 'Warning', 'Minor', 'Major', 'Critical', 'Unknown', 'Unmanaged', 'Disabled', 'Testing']);
+
+const statusFilterModeOptions: Array<SelectableValue<NetXMSQuery['statusFilterMode']>> = [
+  { label: 'Include', value: 'include' },
+  { label: 'Exclude', value: 'exclude' },
+];
+
+const derivativeOptions: Array<SelectableValue<NetXMSQuery['derivative']>> = [
+  { label: 'None', value: 'none' },
+  { label: 'Delta', value: 'delta', description: 'Difference between consecutive values' },
+  { label: 'Rate', value: 'rate', description: 'Change per second' },
+];
+
+const serverAggregationOptions: Array<SelectableValue<NetXMSQuery['serverAggregation']>> = [
+  { label: 'Average', value: 'avg' },
+  { label: 'Minimum', value: 'min' },
+  { label: 'Maximum', value: 'max' },
+  { label: 'Sum', value: 'sum' },
+];
+
+const seriesNameFromOptions: Array<SelectableValue<NetXMSQuery['seriesNameFrom']>> = [
+  { label: 'DCI description', value: 'description' },
+  { label: 'Object name', value: 'objectName', description: 'Not available with all DCIs' },
+  { label: 'Custom', value: 'custom', description: 'Not available with all DCIs' },
+  { label: 'Label', value: 'label' },
+];
+
+// QueryOptions shows optional settings of selected query type. Text and number
+// inputs apply their value on blur, so query isn't run on every key press.
+export function QueryOptions({ query, onChange, onRunQuery }: Props) {
+  const update = (changes: Partial<NetXMSQuery>) => {
+    onChange({ ...query, ...changes });
+    onRunQuery();
+  };
+
+  const set = <K extends keyof NetXMSQuery>(key: K, value: NetXMSQuery[K]) => {
+    update({ [key]: value } as Partial<NetXMSQuery>);
+  };
+
+  const switchField = (key: SwitchOption, label: string, tooltip: string) => (
+    <InlineField label={label} labelWidth={16} tooltip={tooltip}>
+      <InlineSwitch
+        id={`query-editor-${key}`}
+        value={query[key] ?? false}
+        onChange={(event) => set(key, event.currentTarget.checked)}
+      />
+    </InlineField>
+  );
+
+  const textField = (key: TextOption, label: string, tooltip: string, placeholder?: string) => (
+    <InlineField label={label} labelWidth={16} tooltip={tooltip}>
+      <Input
+        id={`query-editor-${key}`}
+        defaultValue={query[key] ?? ''}
+        placeholder={placeholder}
+        onBlur={(event) => set(key, event.currentTarget.value.trim() || undefined)}
+        width={32}
+      />
+    </InlineField>
+  );
+
+  const numberField = (key: NumberOption, label: string, tooltip: string, placeholder?: string) => (
+    <InlineField label={label} labelWidth={16} tooltip={tooltip}>
+      <Input
+        id={`query-editor-${key}`}
+        type="number"
+        defaultValue={query[key] ?? ''}
+        placeholder={placeholder}
+        onBlur={(event) => set(key, event.currentTarget.value ? Number(event.currentTarget.value) : undefined)}
+        width={16}
+      />
+    </InlineField>
+  );
+
+  const listField = (key: ListOption, label: string, tooltip: string) => (
+    <InlineField label={label} labelWidth={16} tooltip={tooltip}>
+      <TagsInput
+        id={`query-editor-${key}`}
+        tags={query[key] ?? []}
+        onChange={(tags) => set(key, tags.length > 0 ? tags : undefined)}
+        width={32}
+      />
+    </InlineField>
+  );
+
+  const multiField = (key: MultiOption, label: string, tooltip: string, options: Array<SelectableValue<string>>) => (
+    <InlineField label={label} labelWidth={16} tooltip={tooltip}>
+      <MultiSelect
+        inputId={`query-editor-${key}`}
+        options={options}
+        value={query[key] ?? []}
+        onChange={(selected) => {
+          const values = selected.map((option) => option.value!);
+          set(key, values.length > 0 ? values : undefined);
+        }}
+        width={32}
+      />
+    </InlineField>
+  );
+
+  // Response handling options shared by most query types
+  const responseFields = (
+    <>
+      {switchField('allowEmptyResponse', 'Allow empty', 'Treat 204 status or empty response as empty result instead of error')}
+      {switchField('rawJson', 'Raw JSON', 'Return unmodified server response for debugging')}
+    </>
+  );
+
+  switch (query.queryType) {
+    case 'alarms':
+      return (
+        <>
+          {textField('messageFilter', 'Message filter', 'Show only alarms with message containing this text')}
+          {switchField('messageFilterRegex', 'Filter as regex', 'Treat message filter as regular expression')}
+          {textField('ackByFilter', 'Acknowledged by', 'Show only alarms acknowledged or resolved by this user')}
+          {listField('fields', 'Columns', 'Alarm columns to include, all columns if empty')}
+          {listField('fieldOrder', 'Column order', 'Alarm columns shown first in given order, other columns follow')}
+          {switchField('logFormat', 'Log format', 'Return alarms as log lines for Logs panel')}
+          {switchField('groupBySource', 'Group by source', 'Group alarms by source object with highest severity and counts')}
+          {switchField('withDciValues', 'DCI values', 'Add latest value of DCI which triggered each alarm')}
+          {responseFields}
+        </>
+      );
+    case 'alarmCount':
+      return (
+        <>
+          {multiField('severityFilter', 'Severities', 'Count only alarms with selected severities', severityOptions)}
+          {multiField('stateFilter', 'States', 'Count only alarms in selected states', stateOptions)}
+          {switchField('countHistory', 'Count history', 'Add alarm repeat count history series when server provides it')}
+          {switchField('allowEmptyResponse', 'Allow empty', 'Treat 204 status or empty response as empty result instead of error')}
+        </>
+      );
+    case 'summaryTables':
+    case 'objectQueries':
+      return (
+        <>
+          {switchField('flattenGroups', 'Flatten groups', 'Flatten nested column groups into "Group/Column" columns')}
+          {query.queryType === 'objectQueries' && textField('timeColumn', 'Time column', 'Column used as time field', 'detected automatically')}
+          {query.queryType === 'objectQueries' && textField('objectIdColumn', 'Object id column', 'Column with object id used for row links', 'detected by name')}
+          {switchField('emptyAsNull', 'Empty as null', 'Show empty string cells as nulls')}
+          {switchField('stringBooleans', 'String booleans', 'Convert "true"/"false" string columns to booleans')}
+          {switchField('transpose', 'Transpose', 'Show single row result as key/value rows')}
+          {textField('frameName', 'Frame name', 'Custom name of result frame')}
+          {responseFields}
+        </>
+      );
+    case 'dciValues':
+      return (
+        <>
+          {switchField('allDcis', 'All DCIs', 'Query history of all DCIs of the object')}
+          {textField('instance', 'Instance', 'Instance of multi-instance DCI')}
+          {switchField('lastValue', 'Last value only', 'Request only the latest DCI value instead of history')}
+          <InlineField label="Aggregation" labelWidth={16} tooltip="Aggregation function applied by server to DCI history">
+            <Select
+              inputId="query-editor-serverAggregation"
+              options={serverAggregationOptions}
+              value={query.serverAggregation}
+              isClearable={true}
+              onChange={(option) => update({ serverAggregation: option?.value })}
+              width={32}
+            />
+          </InlineField>
+          <InlineField label="Derivative" labelWidth={16} tooltip="Convert counter values into differences or per-second rates">
+            <Select
+              inputId="query-editor-derivative"
+              options={derivativeOptions}
+              value={query.derivative ?? 'none'}
+              onChange={(option) => update({ derivative: option?.value })}
+              width={32}
+            />
+          </InlineField>
+          {numberField('percentile', 'Percentile', 'Aggregate values into given percentile (0-100) per query interval', 'e.g. 95')}
+          {switchField('histogram', 'Histogram', 'Return values as histogram buckets with counts')}
+          {query.histogram && numberField('buckets', 'Buckets', 'Number of histogram buckets', '10')}
+          {numberField('maxPoints', 'Max points', 'Maximum number of points in result, evenly sampled')}
+          <InlineField label="Series name" labelWidth={16} tooltip="Source of series name used for frame, value field and legend">
+            <Select
+              inputId="query-editor-seriesNameFrom"
+              options={seriesNameFromOptions}
+              value={query.seriesNameFrom}
+              isClearable={true}
+              onChange={(option) => update({ seriesNameFrom: option?.value })}
+              width={32}
+            />
+          </InlineField>
+          {query.seriesNameFrom === 'custom' && textField('seriesName', 'Custom name', 'Series name')}
+          {query.seriesNameFrom === 'label' && textField('seriesNameLabel', 'Label key', 'Label whose value is used as series name', 'e.g. dci or unit')}
+          {responseFields}
+        </>
+      );
+    case 'objectStatus':
+      return (
+        <>
+          {multiField('statusFilter', 'Status filter', 'Object statuses to include or exclude', statusOptions)}
+          <InlineField label="Filter mode" labelWidth={16} tooltip="Include or exclude objects with selected statuses">
+            <Select
+              inputId="query-editor-statusFilterMode"
+              options={statusFilterModeOptions}
+              value={query.statusFilterMode ?? 'include'}
+              onChange={(option) => update({ statusFilterMode: option?.value })}
+              width={32}
+            />
+          </InlineField>
+          {switchField('statusSummary', 'Status summary', 'Return object count per status instead of frame per object')}
+          {switchField('withStatusSummary', 'With summary', 'Add frame with object count per status after per-object frames')}
+          {numberField('maxFrames', 'Max frames', 'Maximum number of object frames, objects with worst status are kept')}
+          {numberField('depth', 'Depth', 'Status aggregation depth, full subtree if empty')}
+          {responseFields}
+        </>
+      );
+    case 'objectChildren':
+      return (
+        <>
+          {numberField('depth', 'Depth', 'Children depth, 1 returns direct children only')}
+          {responseFields}
+        </>
+      );
+    default:
+      return null;
+  }
+}
@@ -1,7 +1,7 @@
 import { DataSourceInstanceSettings, CoreApp } from '@grafana/data';
 import { DataSourceWithBackend } from '@grafana/runtime';
 
-import { NetXMSQuery, NetxmsSourceOptions as NetXMSDataSourceOptions, DEFAULT_QUERY, ObjectToIdList } from './types';
+import { NetXMSQuery, NetxmsSourceOptions as NetXMSDataSourceOptions, DEFAULT_QUERY, ObjectToIdList, VersionInfo, InterfaceList, TablePreview, EffectiveConfig, DciTypeInfo, AcknowledgeResults } from './types';
 
 export class DataSource extends DataSourceWithBackend<NetXMSQuery, NetXMSDataSourceOptions> {
   constructor(instanceSettings: DataSourceInstanceSettings<NetXMSDataSourceOptions>) {
@@ -36,8 +36,36 @@ export class DataSource extends DataSourceWithBackend<NetXMSQuery, NetXMSDataSou
     return this.getResource('dcis', { name: "objectId", objectId });
   }
 
-  getSummaryTableList(): Promise<ObjectToIdList> {
-    return this.getResource('summaryTables');
+  getSummaryTableList(objectId?: string): Promise<ObjectToIdList> {
+    return this.getResource('summaryTables', objectId ? { objectId } : undefined);
+  }
+
+  getDciType(objectId: string, dciId: string): Promise<DciTypeInfo> {
+    return this.getResource('dciType', { objectId, dciId });
+  }
+
+  getInterfaceList(objectId: string): Promise<InterfaceList> {
+    return this.getResource('interfaces', { objectId });
+  }
+
+  getVersion(): Promise<VersionInfo> {
+    return this.getResource('version');
+  }
+
+  resolveObject(name: string, filter: 'alarm' | 'dci' | 'summary' | 'query'): Promise<ObjectToIdList> {
+    return this.getResource('resolveObject', { name, filter });
+  }
+
+  acknowledgeAlarms(alarmIds: number[]): Promise<AcknowledgeResults> {
+    return this.postResource('acknowledgeAlarms', { alarmIds });
+  }
+
+  getEffectiveConfig(): Promise<EffectiveConfig> {
+    return this.getResource('config');
+  }
+
+  previewTable(query: NetXMSQuery, limit?: number): Promise<TablePreview[]> {
+    return this.postResource('previewTable', { queryType: query.queryType, query, limit });
   }
 
   filterQuery(query: NetXMSQuery): boolean {
@@ -50,9 +78,20 @@ export class DataSource extends DataSourceWithBackend<NetXMSQuery, NetXMSDataSou
         // No required fields for alarms
         return true;
 
+      case 'alarmCount':
+        // All filters are optional
+        return true;
+
+      case 'alarmHistory':
+        // Root object is optional, time range comes from dashboard
+        return true;
+
+      case 'serverInfo':
+        return true;
+
       case 'dciValues':
-        // Both sourceObjectId and dciId are required
-        return !!(query.sourceObjectId && query.dciId);
+        // sourceObjectId is required, dciId is not needed when querying all DCIs
+        return !!(query.sourceObjectId && (query.dciId || query.allDcis));
 
       case 'summaryTables':
         // Both sourceObjectId and summaryTableId are required
@@ -73,6 +112,7 @@ export class DataSource extends DataSourceWithBackend<NetXMSQuery, NetXMSDataSou
         }
         return true;
       case 'objectStatus':
+      case 'objectChildren':
         // sourceObjectId is required
         return !!query.sourceObjectId;
       default:
@@ -4,9 +4,47 @@ import { DataQuery } from '@grafana/schema';
 export interface NetXMSQuery extends DataQuery {
   sourceObjectId?: string;
   dciId?: string;
-  summaryTableId?: string;
+  summaryTableId?: string | string[]; // Multiple ids produce one frame per summary table
   objectQueryId?: string;
   queryParameters?: string; // JSON string of query parameters (Values)
+  messageFilter?: string; // Show only alarms with matching message
+  messageFilterRegex?: boolean; // Treat messageFilter as regular expression instead of substring
+  ackByFilter?: string; // Show only alarms acknowledged or resolved by given user
+  statusFilter?: string[]; // Object status names to include or exclude
+  statusFilterMode?: 'include' | 'exclude';
+  statusSummary?: boolean; // Return single frame with object count per status instead of frame per object
+  withStatusSummary?: boolean; // Add frame with object count per status after per-object frames
+  maxFrames?: number; // Maximum number of object status frames, objects with worst status are kept
+  severityFilter?: string[]; // Alarm severities counted by alarm count query
+  stateFilter?: string[]; // Alarm states counted by alarm count query
+  countHistory?: boolean; // Add alarm repeat count history series when server provides it
+  fields?: string[]; // Alarm columns to include, all columns if not set
+  fieldOrder?: string[]; // Alarm columns shown first in given order, other columns follow in default order
+  logFormat?: boolean; // Return alarms as log lines for Logs panel
+  groupBySource?: boolean; // Group alarms by source object with highest severity and total counts
+  withDciValues?: boolean; // Add latest value of DCI which triggered each alarm
+  depth?: number; // Object children query depth, 1 returns direct children only; object status aggregation depth, full subtree if not set
+  allDcis?: boolean; // Query history of all DCIs of the object
+  instance?: string; // Instance of multi-instance DCI
+  lastValue?: boolean; // Request only the latest DCI value instead of history
+  maxPoints?: number; // Maximum number of DCI points in result, evenly sampled after derivative or percentile is applied
+  derivative?: 'none' | 'delta' | 'rate'; // Convert counter DCI values into differences or per-second rates, counter resets are skipped
+  percentile?: number; // Aggregate DCI values into given percentile (0-100) per query interval, e.g. 95
+  serverAggregation?: 'avg' | 'min' | 'max' | 'sum'; // Aggregation function applied by server to DCI history
+  histogram?: boolean; // Return DCI values as histogram buckets with counts
+  buckets?: number; // Number of histogram buckets (default 10)
+  seriesNameFrom?: 'description' | 'objectName' | 'custom' | 'label'; // Source of DCI series name used for frame, value field and legend, value field is named "value" if not set
+  seriesName?: string; // Series name used when seriesNameFrom is custom
+  seriesNameLabel?: string; // Label key whose value is used as series name when seriesNameFrom is label, e.g. "dci" or "unit"
+  flattenGroups?: boolean; // Flatten nested column groups into "Group/Column" columns
+  timeColumn?: string; // Object query column used as time field, detected automatically if not set
+  emptyAsNull?: boolean; // Show empty string cells of table queries as nulls
+  stringBooleans?: boolean; // Convert "true"/"false" string columns of table queries to booleans
+  objectIdColumn?: string; // Object query column with object id used for row links, detected by name if not set
+  transpose?: boolean; // Show single row result of table query as key/value rows
+  frameName?: string; // Custom name for table query result frame
+  rawJson?: boolean; // Return unmodified server response for debugging
+  allowEmptyResponse?: boolean; // Treat 204 or empty body as empty result instead of error
 }
 
 export const DEFAULT_QUERY: Partial<NetXMSQuery> = {
@@ -28,6 +66,29 @@ export interface DataSourceResponse {
 export interface NetxmsSourceOptions extends DataSourceJsonData {
   serverAddress: string;
   apiKey: string;
+  sortObjects?: boolean; // Sort object lists by name (default true)
+  showObjectPaths?: boolean; // Show full object path (e.g. Site/Rack/Node) in object list labels (default false)
+  timeout?: number; // Request timeout in seconds (default 10)
+  connectTimeout?: number; // Connection establishment timeout in seconds (default 5)
+  http2?: 'auto' | 'disabled' | 'forced'; // HTTP/2 usage, forced also enables HTTP/2 over plain HTTP (default auto)
+  retryOnRateLimit?: boolean; // Retry request once after delay from Retry-After header of 429 response, within request timeout (default true)
+  timeouts?: Record<string, number>; // Request timeout overrides in seconds by query type, fractions allowed, e.g. { dciValues: 60, alarms: 2.5 }
+  statusColors?: Record<string, string>; // Object status color overrides by status name or custom status text from server, "hidden" excludes objects
+  objectQueryCacheTTL?: number; // Object query result cache time in seconds (0 disables caching)
+  defaultTimeRange?: number; // Fallback time range in seconds for queries without range (default 3600)
+  webUiAddress?: string; // NetXMS web UI base address for drill-down links
+  apiVersion?: string; // Grafana API version requested from NetXMS server (default 1)
+  slowResponseThresholdMs?: number; // Response time in milliseconds after which slow response notice is shown (default 5000)
+  failureMode?: 'error' | 'empty'; // Failed query result: error (default) or empty frame with error notice, so other panels aren't affected
+  maxConcurrentRequests?: number; // Maximum number of concurrent requests to NetXMS server (default 10)
+  maxQueriesPerRequest?: number; // Maximum number of queries processed per request, excess queries fail (default 100)
+  gzipRequests?: boolean; // Compress large request bodies once server advertises gzip support
+  gzipRequestThreshold?: number; // Minimum request body size in bytes to compress (default 65536)
+  lenientJson?: boolean; // Tolerate trailing commas and bare NaN/Infinity numbers in responses of older servers, may hide malformed data
+  nullValues?: string[]; // DCI values treated as missing data, defaults to "N/A" and "-"
+  healthyStatuses?: number[]; // HTTP statuses treated as successful server response (default [200])
+  serverTimezone?: string; // NetXMS server time zone (e.g. Europe/Riga) for DCI history time bounds, UTC if not set
+  allowAlarmAcknowledge?: boolean; // Allow Grafana editors and admins to acknowledge alarms using datasource API key (default false)
 }
 
 /**
@@ -43,3 +104,42 @@ export interface ObjectToIdList {
     id: number;
   }>;
 }
+
+export interface VersionInfo {
+  pluginVersion: string;
+  serverVersion: string;
+}
+
+export interface EffectiveConfig extends NetxmsSourceOptions {
+  apiKeyConfigured: boolean;
+}
+
+export interface DciTypeInfo {
+  dataType: string; // DCI data type, e.g. "float", "string" or "table"
+  kind: 'numeric' | 'string' | 'table' | 'unknown';
+}
+
+export interface AcknowledgeResults {
+  results: Array<{
+    id: number;
+    success: boolean;
+    error?: string;
+  }>;
+}
+
+export interface InterfaceList {
+  objects: Array<{
+    id: number;
+    name: string;
+    ifIndex: number;
+    adminState: string;
+    operState: string;
+  }>;
+}
+
+export interface TablePreview {
+  name: string;
+  columns: string[];
+  rows: unknown[][];
+  totalRows: number;
+}